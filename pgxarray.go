@@ -0,0 +1,287 @@
+package pgxhelpers
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// SetArrayField sets a Go slice to a pgtype.Array of T
+// It accepts []E, []*E and nested slices ([][]E, ...) for multi-dimensional arrays, elements are converted
+// with the matching Set*FieldE function, so nil pointers and null strings become NULL elements
+// If the value is nil, not a slice or an element cannot be converted, it returns a pgtype.Array with false
+// It's useful for converting a Go slice to a text[], int4[], uuid[], numeric[], ... column
+// @param v any - The slice to convert to a pgtype.Array
+// @return pgtype.Array[T] - The converted pgtype.Array
+func SetArrayField[T PgScalar](v any) pgtype.Array[T] {
+	out, _ := SetArrayFieldE[T](v)
+	return out
+}
+
+// SetArrayFieldE is the strict variant of SetArrayField
+// It returns a *ConvertError when the value is not a slice, a nested slice is not rectangular or an element cannot be converted
+// A nil value or nil slice is not an error, it returns a pgtype.Array with false
+// @param v any - The slice to convert to a pgtype.Array
+// @return pgtype.Array[T] - The converted pgtype.Array
+// @return error - The conversion error, if any
+func SetArrayFieldE[T PgScalar](v any) (pgtype.Array[T], error) {
+	var out pgtype.Array[T]
+
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return out, nil
+	}
+	if !isArrayLevel(rv.Type()) {
+		if rv.Kind() == reflect.Pointer {
+			return out, nil
+		}
+		return out, newConvertError[pgtype.Array[T]](v, ErrUnsupportedType)
+	}
+	if rv.Kind() == reflect.Slice && rv.IsNil() {
+		return out, nil
+	}
+
+	// one dimension per nesting level of the Go type
+	var dims []pgtype.ArrayDimension
+	for t := rv.Type(); isArrayLevel(t); t = t.Elem() {
+		dims = append(dims, pgtype.ArrayDimension{LowerBound: 1})
+	}
+
+	// the lengths come from the first sub-slice of each level, walk checks the others match
+	for level, lv := 0, rv; level < len(dims); level++ {
+		dims[level].Length = int32(lv.Len())
+		if lv.Len() == 0 {
+			break
+		}
+		lv = lv.Index(0)
+	}
+
+	var walk func(level int, lv reflect.Value) error
+	walk = func(level int, lv reflect.Value) error {
+		if int32(lv.Len()) != dims[level].Length {
+			return fmt.Errorf("%w: multi-dimensional arrays must have sub-arrays with matching dimensions", ErrInvalidFormat)
+		}
+		for i := 0; i < lv.Len(); i++ {
+			if level+1 < len(dims) {
+				if err := walk(level+1, lv.Index(i)); err != nil {
+					return err
+				}
+				continue
+			}
+
+			el, err := setArrayElement[T](lv.Index(i))
+			if err != nil {
+				return err
+			}
+			out.Elements = append(out.Elements, el)
+		}
+		return nil
+	}
+	if err := walk(0, rv); err != nil {
+		return pgtype.Array[T]{}, newConvertError[pgtype.Array[T]](v, err)
+	}
+
+	if len(out.Elements) == 0 {
+		// a non-nil empty dimension list is encoded as '{}', a nil one as NULL
+		dims = []pgtype.ArrayDimension{}
+		out.Elements = []T{}
+	}
+	out.Dims = dims
+	out.Valid = true
+	return out, nil
+}
+
+// SetFlatArrayField sets a one-dimensional Go slice to a pgtype.FlatArray of T
+// Elements are converted like SetArrayField, a nil slice returns a nil pgtype.FlatArray which is stored as NULL
+// If the value is not a one-dimensional slice or an element cannot be converted, it returns nil
+// It's useful for converting a Go slice when multi-dimensional arrays are not needed
+// @param v any - The slice to convert to a pgtype.FlatArray
+// @return pgtype.FlatArray[T] - The converted pgtype.FlatArray
+func SetFlatArrayField[T PgScalar](v any) pgtype.FlatArray[T] {
+	out, _ := SetFlatArrayFieldE[T](v)
+	return out
+}
+
+// SetFlatArrayFieldE is the strict variant of SetFlatArrayField
+// It returns a *ConvertError when the value is not a one-dimensional slice or an element cannot be converted
+// @param v any - The slice to convert to a pgtype.FlatArray
+// @return pgtype.FlatArray[T] - The converted pgtype.FlatArray
+// @return error - The conversion error, if any
+func SetFlatArrayFieldE[T PgScalar](v any) (pgtype.FlatArray[T], error) {
+	arr, err := SetArrayFieldE[T](v)
+	if err != nil || !arr.Valid {
+		return nil, err
+	}
+	if len(arr.Dims) > 1 {
+		return nil, newConvertError[pgtype.FlatArray[T]](v, fmt.Errorf("%w: %d-dimensional slice", ErrUnsupportedType, len(arr.Dims)))
+	}
+	return pgtype.FlatArray[T](arr.Elements), nil
+}
+
+// isArrayLevel reports whether a Go type is a dimension of a PostgreSQL array
+// Slices and arrays are dimensions, except byte arrays and byte slices which are element values (e.g. a uuid)
+// @param t reflect.Type - The type to check
+// @return bool - True if the type is a dimension
+func isArrayLevel(t reflect.Type) bool {
+	if t == nil || (t.Kind() != reflect.Slice && t.Kind() != reflect.Array) {
+		return false
+	}
+	return t.Elem().Kind() != reflect.Uint8
+}
+
+// setArrayElement converts one element of a Go slice to T
+// @param ev reflect.Value - The element
+// @return T - The converted element, invalid for nil pointers
+// @return error - The conversion error, if any
+func setArrayElement[T PgScalar](ev reflect.Value) (T, error) {
+	var zero T
+	if ev.Kind() == reflect.Pointer || ev.Kind() == reflect.Interface {
+		if ev.IsNil() {
+			return zero, nil
+		}
+		if ev.Kind() == reflect.Pointer && ev.Elem().Kind() != reflect.Struct {
+			ev = ev.Elem()
+		}
+	}
+	return SetField[T](ev.Interface())
+}
+
+// RevertArrayField reverts a pgtype.Array or pgtype.FlatArray to a Go slice of E
+// Multi-dimensional arrays are flattened in row-major order, NULL elements become nil for pointer types and zero values otherwise
+// If the value is NULL or an element cannot be converted, it returns nil
+// It's useful for converting a text[], int4[], uuid[], numeric[], ... column to a Go slice
+// @param v any - The pgtype.Array or pgtype.FlatArray to convert
+// @return []E - The converted slice
+func RevertArrayField[E any](v any) []E {
+	out, _ := RevertArrayFieldE[E](v)
+	return out
+}
+
+// RevertArrayFieldE is the strict variant of RevertArrayField
+// It returns a *ConvertError when the value is not an array or an element cannot be converted to E
+// @param v any - The pgtype.Array or pgtype.FlatArray to convert
+// @return []E - The converted slice
+// @return error - The conversion error, if any
+func RevertArrayFieldE[E any](v any) ([]E, error) {
+	var out []E
+	if err := RevertArrayInto(v, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RevertArrayInto reverts a pgtype.Array or pgtype.FlatArray into the slice dst points to
+// dst can be a pointer to a nested slice with one level per array dimension (e.g. *[][]int64 for a 2-dimensional array)
+// or a pointer to a flat slice, NULL sets the slice to nil
+// It's useful for converting multi-dimensional arrays back to nested Go slices
+// @param v any - The pgtype.Array or pgtype.FlatArray to convert
+// @param dst any - A pointer to the destination slice
+// @return error - The conversion error, if any
+func RevertArrayInto(v any, dst any) error {
+	dv := reflect.ValueOf(dst)
+	if dv.Kind() != reflect.Pointer || dv.IsNil() || dv.Elem().Kind() != reflect.Slice {
+		return &ConvertError{Input: v, Target: fmt.Sprintf("%T", dst), Err: fmt.Errorf("%w: destination must be a pointer to a slice", ErrUnsupportedType)}
+	}
+	dv = dv.Elem()
+
+	arr, valid, ok := arrayFromAny(v)
+	if !ok {
+		return revertError(v, dv, ErrUnsupportedType)
+	}
+	if !valid {
+		dv.SetZero()
+		return nil
+	}
+
+	dims := arr.Dimensions()
+	depth := 0
+	for t := dv.Type(); isArrayLevel(t); t = t.Elem() {
+		depth++
+	}
+	if len(dims) == 0 {
+		dv.Set(reflect.MakeSlice(dv.Type(), 0, 0))
+		return nil
+	}
+	if depth != 1 && depth != len(dims) {
+		return revertError(v, dv, fmt.Errorf("%w: %d-dimensional array into %d-dimensional slice", ErrUnsupportedType, len(dims), depth))
+	}
+	if depth == 1 {
+		// flatten
+		dims = []pgtype.ArrayDimension{{Length: int32(cardinality(dims)), LowerBound: 1}}
+	}
+
+	index := 0
+	var build func(level int, sv reflect.Value) error
+	build = func(level int, sv reflect.Value) error {
+		n := int(dims[level].Length)
+		sv.Set(reflect.MakeSlice(sv.Type(), n, n))
+		for i := 0; i < n; i++ {
+			if level+1 < len(dims) {
+				if err := build(level+1, sv.Index(i)); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := revertScalarInto(arr.Index(index), sv.Index(i)); err != nil {
+				return err
+			}
+			index++
+		}
+		return nil
+	}
+	if err := build(0, dv); err != nil {
+		dv.SetZero()
+		return err
+	}
+	return nil
+}
+
+// arrayFromAny extracts a pgtype.ArrayGetter from a pgtype.Array, pgtype.FlatArray or a pointer to one
+// @param v any - The value to extract the array from
+// @return pgtype.ArrayGetter - The array
+// @return bool - False if the array is NULL
+// @return bool - False if the value is not an array
+func arrayFromAny(v any) (pgtype.ArrayGetter, bool, bool) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil, false, true
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return nil, false, true
+	}
+
+	arr, ok := rv.Interface().(pgtype.ArrayGetter)
+	if !ok {
+		return nil, false, false
+	}
+	switch rv.Kind() {
+	case reflect.Slice:
+		return arr, !rv.IsNil(), true
+	case reflect.Struct:
+		if valid := rv.FieldByName("Valid"); valid.IsValid() && valid.Kind() == reflect.Bool {
+			return arr, valid.Bool(), true
+		}
+	}
+	return arr, true, true
+}
+
+// cardinality returns the number of elements of an array with the given dimensions
+// @param dims []pgtype.ArrayDimension - The dimensions
+// @return int - The number of elements
+func cardinality(dims []pgtype.ArrayDimension) int {
+	if len(dims) == 0 {
+		return 0
+	}
+	n := 1
+	for _, d := range dims {
+		n *= int(d.Length)
+	}
+	return n
+}
@@ -0,0 +1,188 @@
+package pgxhelpers
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/ChungNQ511/vnw-helpers/funcvx"
+)
+
+// DefaultByteaMaxSize is the default maximum number of bytes SetByteaField reads from an io.Reader
+const DefaultByteaMaxSize = 16 << 20
+
+// ByteaEncoding is the text encoding used by RevertPgByteaString and required by WithByteaEncoding
+type ByteaEncoding int
+
+const (
+	// ByteaHex is PostgreSQL's hex format, e.g. "\x48656c6c6f"
+	ByteaHex ByteaEncoding = iota
+	// ByteaBase64 is standard base64 with padding
+	ByteaBase64
+	// ByteaBase64URL is URL-safe base64 with padding
+	ByteaBase64URL
+)
+
+// ByteaOption configures how SetByteaField converts a value to bytea
+type ByteaOption func(*byteaConfig)
+
+// byteaConfig holds the options of SetByteaField
+type byteaConfig struct {
+	maxSize     int64
+	emptyAsNull bool
+	encoding    *ByteaEncoding
+}
+
+// WithByteaMaxSize sets the maximum number of bytes accepted, larger inputs return ErrOverflow
+// It defaults to DefaultByteaMaxSize
+// @param n int64 - The maximum number of bytes
+// @return ByteaOption - The option
+func WithByteaMaxSize(n int64) ByteaOption {
+	return func(c *byteaConfig) {
+		c.maxSize = n
+	}
+}
+
+// WithByteaEncoding requires strings to be in the given encoding instead of guessing it
+// Without it, a string without the "\x" prefix is decoded as any base64 that accepts it, so a plain word like "abcd" is decoded too
+// @param enc ByteaEncoding - ByteaHex for "\x..." literals, ByteaBase64 or ByteaBase64URL for padded base64
+// @return ByteaOption - The option
+func WithByteaEncoding(enc ByteaEncoding) ByteaOption {
+	return func(c *byteaConfig) {
+		c.encoding = &enc
+	}
+}
+
+// WithEmptyByteaAsNull stores empty values as SQL NULL instead of an empty bytea
+// @return ByteaOption - The option
+func WithEmptyByteaAsNull() ByteaOption {
+	return func(c *byteaConfig) {
+		c.emptyAsNull = true
+	}
+}
+
+// SetByteaField sets a []byte, io.Reader, hex "\x..." string or base64 string to a []byte for a bytea column
+// pgx stores a nil []byte as NULL and a non-nil empty []byte as an empty bytea
+// nil values and null strings are NULL, empty values are an empty bytea unless WithEmptyByteaAsNull is set
+// Strings without the "\x" prefix are decoded as standard or URL-safe base64, padded or not, and any string that happens
+// to be valid base64 is decoded without a signal, use WithByteaEncoding to require one encoding
+// If the value is not supported, cannot be decoded or is larger than the maximum size, it returns nil
+// It's useful for converting signatures, thumbnails and other binary data to a bytea column
+// @param v any - The value to convert to a []byte
+// @param opts ...ByteaOption - The size cap, string encoding and empty value policy
+// @return []byte - The converted []byte, nil for NULL
+func SetByteaField(v any, opts ...ByteaOption) []byte {
+	out, _ := SetByteaFieldE(v, opts...)
+	return out
+}
+
+// SetByteaFieldE is the strict variant of SetByteaField
+// It returns a *ConvertError when the value is not supported, the string is neither hex nor base64 or not in the WithByteaEncoding encoding,
+// reading fails or the value is larger than the maximum size (ErrOverflow)
+// For ErrOverflow the Input of the *ConvertError is the type name of v, e.g. "*os.File", instead of v itself,
+// so the error neither keeps an oversized payload alive nor prints it
+// @param v any - The value to convert to a []byte
+// @param opts ...ByteaOption - The size cap, string encoding and empty value policy
+// @return []byte - The converted []byte, nil for NULL
+// @return error - The conversion error, if any
+func SetByteaFieldE(v any, opts ...ByteaOption) ([]byte, error) {
+	cfg := byteaConfig{maxSize: DefaultByteaMaxSize}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	var (
+		out []byte
+		err error
+	)
+	switch val := v.(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		if val == nil {
+			return nil, nil
+		}
+		out = val
+	case *[]byte:
+		if val == nil {
+			return nil, nil
+		}
+		return SetByteaFieldE(*val, opts...)
+	case string:
+		if !funcvx.NotNull(val) {
+			return nil, nil
+		}
+		out, err = decodeByteaString(val, cfg.encoding)
+	case *string:
+		if val == nil {
+			return nil, nil
+		}
+		return SetByteaFieldE(*val, opts...)
+	case io.Reader:
+		// read one byte more than the cap to detect larger inputs, MaxInt64 has no room for it and is no cap
+		limit := cfg.maxSize
+		if limit < math.MaxInt64 {
+			limit++
+		}
+		out, err = io.ReadAll(io.LimitReader(val, limit))
+		if out == nil && err == nil {
+			out = []byte{}
+		}
+	default:
+		err = ErrUnsupportedType
+	}
+	if err != nil {
+		return nil, &ConvertError{Input: v, Target: "bytea", Err: err}
+	}
+
+	if int64(len(out)) > cfg.maxSize {
+		// the type name, not the payload, see the doc comment
+		return nil, &ConvertError{Input: fmt.Sprintf("%T", v), Target: "bytea", Err: fmt.Errorf("%w: more than %d bytes", ErrOverflow, cfg.maxSize)}
+	}
+	if len(out) == 0 && cfg.emptyAsNull {
+		return nil, nil
+	}
+	return out, nil
+}
+
+// decodeByteaString decodes a PostgreSQL hex bytea literal ("\x48656c6c6f") or a base64 string
+// @param s string - The encoded string
+// @param enc *ByteaEncoding - The required encoding, nil accepts a hex literal or any base64
+// @return []byte - The decoded bytes
+// @return error - ErrInvalidFormat if the string is neither hex nor base64, or not in the required encoding
+func decodeByteaString(s string, enc *ByteaEncoding) ([]byte, error) {
+	s = strings.TrimSpace(s)
+	isHex := strings.HasPrefix(s, `\x`) || strings.HasPrefix(s, `\X`)
+
+	if enc != nil && *enc != ByteaHex {
+		encoding := funcvx.Ternary(*enc == ByteaBase64URL, base64.URLEncoding, base64.StdEncoding)
+		b, err := encoding.DecodeString(s)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid base64 bytea", ErrInvalidFormat)
+		}
+		return b, nil
+	}
+	if enc != nil && !isHex {
+		return nil, fmt.Errorf(`%w: expected a \x hex literal`, ErrInvalidFormat)
+	}
+
+	if isHex {
+		// PostgreSQL allows whitespace between hex digit pairs
+		digits := strings.Join(strings.Fields(s[2:]), "")
+		b, err := hex.DecodeString(digits)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid hex bytea", ErrInvalidFormat)
+		}
+		return b, nil
+	}
+
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		if b, err := enc.DecodeString(s); err == nil {
+			return b, nil
+		}
+	}
+	return nil, fmt.Errorf(`%w: expected a \x hex literal or base64`, ErrInvalidFormat)
+}
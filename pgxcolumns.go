@@ -0,0 +1,361 @@
+package pgxhelpers
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/ChungNQ511/vnw-helpers/funcvx"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// columnConverters maps the type= names used in db tags to the strict Set*FieldE converters
+var columnConverters = map[string]func(v any) (any, error){
+	"text":        columnConverter(SetTextFieldE),
+	"varchar":     columnConverter(SetTextFieldE),
+	"int2":        columnConverter(SetIntFieldE[pgtype.Int2]),
+	"smallint":    columnConverter(SetIntFieldE[pgtype.Int2]),
+	"int4":        columnConverter(SetIntFieldE[pgtype.Int4]),
+	"int":         columnConverter(SetIntFieldE[pgtype.Int4]),
+	"integer":     columnConverter(SetIntFieldE[pgtype.Int4]),
+	"int8":        columnConverter(SetIntFieldE[pgtype.Int8]),
+	"bigint":      columnConverter(SetIntFieldE[pgtype.Int8]),
+	"float4":      columnConverter(SetFloatFieldE[pgtype.Float4]),
+	"real":        columnConverter(SetFloatFieldE[pgtype.Float4]),
+	"float8":      columnConverter(SetFloatFieldE[pgtype.Float8]),
+	"numeric":     func(v any) (any, error) { return SetNumericFieldE(v) },
+	"decimal":     func(v any) (any, error) { return SetNumericFieldE(v) },
+	"bool":        columnConverter(SetBoolFieldE),
+	"boolean":     columnConverter(SetBoolFieldE),
+	"date":        columnConverter(SetDateFieldE),
+	"timestamp":   columnConverter(SetTimestampFieldE),
+	"timestamptz": columnConverter(SetTimestamptzFieldE),
+	"time":        columnConverter(SetTimeFieldE),
+	"interval":    columnConverter(SetIntervalFieldE),
+	"uuid":        columnConverter(SetUUIDFieldE),
+	"json":        func(v any) (any, error) { return SetJSONFieldE(v) },
+	"jsonb":       func(v any) (any, error) { return SetJSONFieldE(v) },
+	"bytea":       func(v any) (any, error) { return SetByteaFieldE(v) },
+	"hstore":      columnConverter(SetHstoreFieldE),
+	"inet":        columnConverter(SetInetFieldE),
+	"cidr":        columnConverter(SetCidrFieldE),
+	"macaddr":     columnConverter(SetMacaddrFieldE),
+	"point":       columnConverter(SetPointFieldE),
+	"box":         columnConverter(SetBoxFieldE),
+}
+
+// columnConverter adapts a Set*FieldE function to the columnConverters signature
+func columnConverter[T any](f func(v any) (T, error)) func(v any) (any, error) {
+	return func(v any) (any, error) {
+		return f(v)
+	}
+}
+
+// convertColumn converts a field value to the argument for a column of the type named in its db tag
+// Without a type the value is returned as is for pgx to encode
+// @param name string - The column name, used in errors
+// @param typ string - The type= name from the db tag
+// @param v any - The field value
+// @return any - The query argument
+// @return error - The conversion error, if any
+func convertColumn(name, typ string, v any) (any, error) {
+	if typ == "" {
+		return v, nil
+	}
+	conv, ok := columnConverters[typ]
+	if !ok {
+		return nil, fmt.Errorf("pgxhelpers: column %q: unknown type %q", name, typ)
+	}
+	out, err := conv(v)
+	if err != nil {
+		return nil, fmt.Errorf("pgxhelpers: column %q: %w", name, err)
+	}
+	return out, nil
+}
+
+// dbTag is a parsed `db:"name,type=int4,omitempty,nullempty"` struct tag
+type dbTag struct {
+	name      string
+	typ       string
+	omitEmpty bool
+	nullEmpty bool
+}
+
+// parseDBTag parses the db tag of a struct field
+// The name defaults to the snake_case field name, ok is false for fields tagged `db:"-"` and unexported fields
+// @param f reflect.StructField - The struct field
+// @return dbTag - The parsed tag
+// @return bool - False if the field is skipped
+func parseDBTag(f reflect.StructField) (dbTag, bool) {
+	tag := f.Tag.Get("db")
+	if tag == "-" || !f.IsExported() {
+		return dbTag{}, false
+	}
+
+	parts := strings.Split(tag, ",")
+	out := dbTag{name: strings.TrimSpace(parts[0])}
+	if out.name == "" {
+		out.name = toSnakeCase(f.Name)
+	}
+	for _, opt := range parts[1:] {
+		opt = strings.TrimSpace(opt)
+		switch {
+		case opt == "omitempty":
+			out.omitEmpty = true
+		case opt == "nullempty":
+			out.nullEmpty = true
+		default:
+			if typ, ok := strings.CutPrefix(opt, "type="); ok {
+				out.typ = strings.ToLower(typ)
+			}
+		}
+	}
+	return out, true
+}
+
+// columnField is a struct field mapped to a column
+type columnField struct {
+	index []int
+	tag   dbTag
+}
+
+// columnFieldsCache caches the column fields of each struct type, map[reflect.Type][]columnField
+var columnFieldsCache sync.Map
+
+// columnFields returns the column fields of a struct type, inspecting the type only once
+// @param t reflect.Type - The struct type
+// @return []columnField - The column fields, in field order with embedded structs and struct pointers flattened
+func columnFields(t reflect.Type) []columnField {
+	if cached, ok := columnFieldsCache.Load(t); ok {
+		return cached.([]columnField)
+	}
+	fields, _ := columnFieldsCache.LoadOrStore(t, collectColumnFields(t, nil, nil))
+	return fields.([]columnField)
+}
+
+// collectColumnFields walks the fields of a struct type, embedded structs and struct pointers without a db tag are flattened
+// An embedded pointer to a struct that is already being walked is skipped, so recursive types terminate
+// @param t reflect.Type - The struct type
+// @param index []int - The index of t in the outer struct, nil for the outer struct
+// @param parents []reflect.Type - The struct types being walked
+// @return []columnField - The column fields, in field order
+func collectColumnFields(t reflect.Type, index []int, parents []reflect.Type) []columnField {
+	parents = append(slices.Clone(parents), t)
+	var out []columnField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		fieldIndex := append(slices.Clone(index), i)
+		if f.Anonymous && f.Tag.Get("db") == "" {
+			embedded := f.Type
+			if embedded.Kind() == reflect.Pointer {
+				if !f.IsExported() {
+					continue // reflect cannot allocate an unexported embedded pointer
+				}
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				if !slices.Contains(parents, embedded) {
+					out = append(out, collectColumnFields(embedded, fieldIndex, parents)...)
+				}
+				continue
+			}
+		}
+
+		tag, ok := parseDBTag(f)
+		if !ok {
+			continue
+		}
+		out = append(out, columnField{index: fieldIndex, tag: tag})
+	}
+	return out
+}
+
+// columnValue returns the value of a column field
+// @param rv reflect.Value - The struct value
+// @param f columnField - The column field
+// @return reflect.Value - The field value
+// @return bool - False if the field is inside a nil embedded struct pointer
+func columnValue(rv reflect.Value, f columnField) (reflect.Value, bool) {
+	fv, err := rv.FieldByIndexErr(f.index)
+	return fv, err == nil
+}
+
+// settableColumnValue returns the value of a column field, allocating nil embedded struct pointers on the way
+// @param rv reflect.Value - The settable struct value
+// @param f columnField - The column field
+// @return reflect.Value - The settable field value
+func settableColumnValue(rv reflect.Value, f columnField) reflect.Value {
+	for i, x := range f.index {
+		if i > 0 && rv.Kind() == reflect.Pointer {
+			if rv.IsNil() {
+				rv.Set(reflect.New(rv.Type().Elem()))
+			}
+			rv = rv.Elem()
+		}
+		rv = rv.Field(x)
+	}
+	return rv
+}
+
+// structValue dereferences v down to a struct value
+// @param v any - The struct, or a pointer to it
+// @param caller string - The name of the calling function, used in errors
+// @return reflect.Value - The struct value
+// @return error - An error if v is nil or not a struct
+func structValue(v any, caller string) (reflect.Value, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return reflect.Value{}, fmt.Errorf("pgxhelpers: %s of nil %T", caller, v)
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("pgxhelpers: %s of non-struct %T", caller, v)
+	}
+	return rv, nil
+}
+
+// nullableField is implemented by every Nullable[T]
+type nullableField interface {
+	nullableState() (value any, valid bool)
+}
+
+var (
+	optionalFieldType = reflect.TypeOf((*optionalField)(nil)).Elem()
+	nullableFieldType = reflect.TypeOf((*nullableField)(nil)).Elem()
+)
+
+// columnArg converts a field to its query argument
+// Optional fields that are absent are skipped, Optional and Nullable fields are unwrapped to their value or nil,
+// omitempty skips empty values and nullempty writes them as NULL, explicit nulls of Optional fields are always written
+// A nil *Optional is absent and a nil *Nullable is NULL
+// @param f columnField - The column field
+// @param fv reflect.Value - The field value
+// @return any - The query argument
+// @return bool - False if the column is skipped
+// @return error - The conversion error, if any
+func columnArg(f columnField, fv reflect.Value) (any, bool, error) {
+	value := fv.Interface()
+	explicitNull := false
+	if fv.Kind() == reflect.Pointer && fv.IsNil() {
+		switch {
+		case fv.Type().Implements(optionalFieldType):
+			return nil, false, nil
+		case fv.Type().Implements(nullableFieldType):
+			value = nil
+		}
+	}
+	switch val := value.(type) {
+	case optionalField:
+		v, present, valid := val.optionalState()
+		if !present {
+			return nil, false, nil
+		}
+		value, explicitNull = funcvx.Ternary(valid, v, nil), !valid
+	case nullableField:
+		v, valid := val.nullableState()
+		value = funcvx.Ternary(valid, v, nil)
+	}
+
+	if !explicitNull && (f.tag.omitEmpty || f.tag.nullEmpty) && (value == nil || reflect.ValueOf(value).IsZero()) {
+		if f.tag.omitEmpty {
+			return nil, false, nil
+		}
+		value = nil
+	}
+
+	arg, err := convertColumn(f.tag.name, f.tag.typ, value)
+	if err != nil {
+		return nil, false, err
+	}
+	return arg, true, nil
+}
+
+// StructArgs builds a column list and query arguments from the fields of a struct
+// Columns are named by the `db:"name,type=int4,omitempty,nullempty"` tag or the snake_case field name, `db:"-"` skips a field
+// With a type the value goes through the matching Set*FieldE converter, otherwise it's passed to pgx as is
+// omitempty leaves out empty values so the column default applies, nullempty stores them as NULL
+// Nullable fields are NULL when not valid, Optional fields are left out when absent
+// Embedded structs and struct pointers are flattened, the fields of a nil embedded pointer are left out
+// The fields of each struct type are inspected once and cached
+// It's useful for INSERT statements with InsertValuesClause and full UPDATE statements with UpdateSetClause
+// @param v any - The struct, or a pointer to it
+// @return []string - The column names
+// @return []any - The query arguments, in the same order
+// @return error - An error if v is not a struct or a value cannot be converted
+func StructArgs(v any) ([]string, []any, error) {
+	rv, err := structValue(v, "StructArgs")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fields := columnFields(rv.Type())
+	columns := make([]string, 0, len(fields))
+	args := make([]any, 0, len(fields))
+	for _, f := range fields {
+		fv, ok := columnValue(rv, f)
+		if !ok {
+			continue
+		}
+		arg, ok, err := columnArg(f, fv)
+		if err != nil {
+			return nil, nil, err
+		}
+		if ok {
+			columns = append(columns, f.tag.name)
+			args = append(args, arg)
+		}
+	}
+	return columns, args, nil
+}
+
+// InsertValuesClause builds the column and VALUES lists of an INSERT, e.g. `("name", "age") VALUES ($1, $2)`
+// @param columns []string - The column names from StructArgs
+// @param start int - The number of the first placeholder
+// @return string - The column and VALUES lists
+// @return error - An error if there are no columns, use DEFAULT VALUES for a row of defaults
+func InsertValuesClause(columns []string, start int) (string, error) {
+	if len(columns) == 0 {
+		return "", errors.New("pgxhelpers: InsertValuesClause without columns")
+	}
+	quoted := make([]string, len(columns))
+	placeholders := make([]string, len(columns))
+	for i, col := range columns {
+		quoted[i] = QuoteIdentifier(col)
+		placeholders[i] = "$" + strconv.Itoa(start+i)
+	}
+	return "(" + strings.Join(quoted, ", ") + ") VALUES (" + strings.Join(placeholders, ", ") + ")", nil
+}
+
+// toSnakeCase converts a Go field name to a snake_case column name
+// Acronyms are kept together, e.g. "UserID" becomes "user_id" and "HTTPStatus" becomes "http_status"
+// @param name string - The field name
+// @return string - The snake_case name
+func toSnakeCase(name string) string {
+	runes := []rune(name)
+	var builder strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				builder.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
+
+// QuoteIdentifier quotes a column or table name for use in SQL, doubling embedded quotes
+// @param name string - The identifier
+// @return string - The quoted identifier
+func QuoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
@@ -0,0 +1,212 @@
+package pgxhelpers
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/ChungNQ511/vnw-helpers/funcvx"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ErrAmbiguousDate is returned when a numeric date reads as a valid date both day first and month first, e.g. "03/04/2024"
+var ErrAmbiguousDate = errors.New("date is valid both day first and month first")
+
+// DateReading describes how a date or time string was read
+// @field Time time.Time - The parsed time
+// @field Layout string - The layout that matched
+// @field Order DateOrder - DateOrderDMY or DateOrderMDY for day-first and month-first layouts, DateOrderYMD for the others
+// @field Ambiguous bool - True if swapping the day and month also gives a valid, different date
+// @field Alternative time.Time - The time with the day and month swapped, set only when Ambiguous
+type DateReading struct {
+	Time        time.Time
+	Layout      string
+	Order       DateOrder
+	Ambiguous   bool
+	Alternative time.Time
+}
+
+// dayMonthLayout matches layouts that start with a day and a month, e.g. "2/1/2006" or "01-02-06"
+var dayMonthLayout = regexp.MustCompile(`^(02|_2|2|01|1)([/.-])(02|_2|2|01|1)([/.-])`)
+
+// swapDayMonth swaps the leading day and month of a layout
+// @param layout string - The layout
+// @return string - The layout with the day and month swapped
+// @return DateOrder - DateOrderDMY or DateOrderMDY, the order of the given layout
+// @return bool - False if the layout does not start with a day and a month
+func swapDayMonth(layout string) (string, DateOrder, bool) {
+	m := dayMonthLayout.FindStringSubmatch(layout)
+	if m == nil {
+		return "", 0, false
+	}
+	first, second := m[1], m[3]
+	isMonth := func(tok string) bool { return tok == "01" || tok == "1" }
+	if isMonth(first) == isMonth(second) {
+		return "", 0, false
+	}
+	swapped := second + m[2] + first + m[4] + layout[len(m[0]):]
+	return swapped, funcvx.Ternary(isMonth(first), DateOrderMDY, DateOrderDMY), true
+}
+
+// ReadDate parses a string with the layouts of the default policy and tells whether its day and month can be swapped
+// It's useful for showing users how an imported date will be read and flagging dates like "03/04/2024"
+// @param s string - The string to parse
+// @return DateReading - The reading, with the alternative reading when ambiguous
+// @return error - A *ConvertError if no layout matches
+func ReadDate(s string) (DateReading, error) {
+	return DefaultConverter().ReadDate(s)
+}
+
+// ReadDate is ReadDate with the rules of the Converter
+// Ambiguous dates are reported, not rejected, even if RejectAmbiguousDates is set
+// @param s string - The string to parse
+// @return DateReading - The reading, with the alternative reading when ambiguous
+// @return error - A *ConvertError if no layout matches
+func (c *Converter) ReadDate(s string) (DateReading, error) {
+	r, err := c.readTime(c.policy.trim(s), c.policy.location())
+	if err != nil {
+		return DateReading{}, newConvertError[time.Time](s, err)
+	}
+	return r, nil
+}
+
+// readTime parses a string with the layouts of the Converter and checks the day-month swapped reading
+// With the built-in layouts, strings no layout matches are tried with the day and month of each layout swapped,
+// a custom Policy.Layouts registry is used as is so a day-first registry never reads a date month first
+// @param s string - The string to parse
+// @param loc *time.Location - The location of zone-less strings
+// @return DateReading - The reading
+// @return error - ErrInvalidFormat if no layout matches
+func (c *Converter) readTime(s string, loc *time.Location) (DateReading, error) {
+	layouts := c.layouts().load()
+	t, layout, ok := c.tryLayouts(layouts, s, loc)
+	if !ok && c.policy.Layouts != nil {
+		return DateReading{}, fmt.Errorf("%w: no matching time layout", ErrInvalidFormat)
+	}
+	if !ok {
+		// dates that only fit the other order, e.g. "4/25/2024" with day-first layouts
+		var swapped []string
+		for _, l := range layouts {
+			if sl, _, isDayMonth := swapDayMonth(l); isDayMonth {
+				swapped = append(swapped, sl)
+			}
+		}
+		if t, layout, ok = c.tryLayouts(swapped, s, loc); !ok {
+			return DateReading{}, fmt.Errorf("%w: no matching time layout", ErrInvalidFormat)
+		}
+	}
+
+	r := DateReading{Time: t, Layout: layout, Order: DateOrderYMD}
+	swapped, order, ok := swapDayMonth(layout)
+	if !ok {
+		return r, nil
+	}
+	r.Order = order
+	if alt, _, ok := c.tryLayouts([]string{swapped}, s, loc); ok && !alt.Equal(t) {
+		r.Ambiguous, r.Alternative = true, alt
+	}
+	return r, nil
+}
+
+// swappedParses reports whether a value also parses with the day and month of its layout swapped
+// @param s string - The value
+// @param r DateReading - The reading of the value
+// @return bool - True if the swapped layout parses, even to the same date
+func (c *Converter) swappedParses(s string, r DateReading) bool {
+	swapped, _, ok := swapDayMonth(r.Layout)
+	if !ok {
+		return false
+	}
+	_, _, ok = c.tryLayouts([]string{swapped}, c.policy.trim(s), c.policy.location())
+	return ok
+}
+
+// InferDateOrder infers the day/month order of a column of date strings with the layouts of the default policy
+// Values like "25/12/2024" can only be day first and "12/25/2024" only month first,
+// values like "03/04/2024" and "03/03/2024" that are valid both ways count for neither
+// Null values and dates without a day and month, such as ISO dates, are skipped
+// If no value decides the order, it returns the DateOrder of the policy with ErrAmbiguousDate if some values were ambiguous
+// @param values []string - The column values
+// @return DateOrder - The inferred order
+// @return error - ErrAmbiguousDate, ErrInvalidFormat if the column mixes both orders, or a *ConvertError for a value no layout matches
+func InferDateOrder(values []string) (DateOrder, error) {
+	return DefaultConverter().InferDateOrder(values)
+}
+
+// InferDateOrder is InferDateOrder with the rules of the Converter
+// @param values []string - The column values
+// @return DateOrder - The inferred order
+// @return error - ErrAmbiguousDate, ErrInvalidFormat if the column mixes both orders, or a *ConvertError for a value no layout matches
+func (c *Converter) InferDateOrder(values []string) (DateOrder, error) {
+	var dayFirst, monthFirst, ambiguous int
+	for i, v := range values {
+		if !funcvx.NotNull(c.policy.trim(v)) {
+			continue
+		}
+		r, err := c.ReadDate(v)
+		if err != nil {
+			return c.policy.DateOrder, fmt.Errorf("pgxhelpers: value %d: %w", i, err)
+		}
+		switch {
+		case r.Ambiguous:
+			ambiguous++
+		case r.Order == DateOrderYMD, c.swappedParses(v, r):
+			// no day and month, or the same date both ways like "03/03/2024"
+		case r.Order == DateOrderDMY:
+			dayFirst++
+		case r.Order == DateOrderMDY:
+			monthFirst++
+		}
+	}
+
+	switch {
+	case dayFirst > 0 && monthFirst > 0:
+		return c.policy.DateOrder, fmt.Errorf("%w: the column has %d day-first and %d month-first dates", ErrInvalidFormat, dayFirst, monthFirst)
+	case dayFirst > 0:
+		return DateOrderDMY, nil
+	case monthFirst > 0:
+		return DateOrderMDY, nil
+	case ambiguous > 0:
+		return c.policy.DateOrder, fmt.Errorf("%w: no date in the column decides the order of %d ambiguous dates", ErrAmbiguousDate, ambiguous)
+	}
+	return c.policy.DateOrder, nil
+}
+
+// SetDateColumnE converts a column of date strings, reading every ambiguous value in the order inferred by InferDateOrder
+// If no value decides the order, the DateOrder of the policy is used, or ErrAmbiguousDate is returned if RejectAmbiguousDates is set
+// Null values give a pgtype.Date with false
+// Dates are read in UTC like SetDateField, whatever the policy location
+// It's useful for imports where one file uses a single date order that is not known in advance
+// @param values []string - The column values
+// @return []pgtype.Date - The converted dates, in the same order
+// @return DateOrder - The order used for ambiguous values
+// @return error - The inference error or the error of the first value that cannot be converted
+func SetDateColumnE(values []string) ([]pgtype.Date, DateOrder, error) {
+	return DefaultConverter().SetDateColumnE(values)
+}
+
+// SetDateColumnE is SetDateColumnE with the rules of the Converter
+// @param values []string - The column values
+// @return []pgtype.Date - The converted dates, in the same order
+// @return DateOrder - The order used for ambiguous values
+// @return error - The inference error or the error of the first value that cannot be converted
+func (c *Converter) SetDateColumnE(values []string) ([]pgtype.Date, DateOrder, error) {
+	order, err := c.InferDateOrder(values)
+	if err != nil && (!errors.Is(err, ErrAmbiguousDate) || c.policy.RejectAmbiguousDates) {
+		return nil, order, err
+	}
+
+	out := make([]pgtype.Date, len(values))
+	for i, v := range values {
+		if !funcvx.NotNull(c.policy.trim(v)) {
+			continue
+		}
+		r, err := c.readTime(c.policy.trim(v), time.UTC)
+		if err != nil {
+			return nil, order, fmt.Errorf("pgxhelpers: value %d: %w", i, newConvertError[pgtype.Date](v, err))
+		}
+		out[i] = pgtype.Date{Time: funcvx.Ternary(r.Ambiguous && r.Order != order, r.Alternative, r.Time), Valid: true}
+	}
+	return out, order, nil
+}
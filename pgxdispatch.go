@@ -0,0 +1,248 @@
+package pgxhelpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/netip"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/ChungNQ511/vnw-helpers/datecvx"
+	"github.com/ChungNQ511/vnw-helpers/strconvx"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// PgType lists every target type SetField converts to and Revert converts from
+// []byte is bytea, json.RawMessage is json and jsonb, netip.Prefix is inet and net.HardwareAddr is macaddr
+type PgType interface {
+	PgScalar | pgtype.Interval | pgtype.Time | pgtype.Hstore | pgtype.Point | pgtype.Box |
+		[]byte | json.RawMessage | netip.Prefix | net.HardwareAddr
+}
+
+// SetField converts a value to the pgtype T with the matching Set*FieldE converter
+// It's the single entry point for code generators and reflection mappers, e.g. SetField[pgtype.Int4](v) is SetIntFieldE[pgtype.Int4](v)
+// Converters with options use their defaults, call them directly to pass options
+// @param v any - The value to convert
+// @return T - The converted value
+// @return error - The conversion error, if any
+func SetField[T PgType](v any) (T, error) {
+	var out T
+	var (
+		res any
+		err error
+	)
+	switch any(out).(type) {
+	case pgtype.Text:
+		res, err = SetTextFieldE(v)
+	case pgtype.Int2:
+		res, err = SetIntFieldE[pgtype.Int2](v)
+	case pgtype.Int4:
+		res, err = SetIntFieldE[pgtype.Int4](v)
+	case pgtype.Int8:
+		res, err = SetIntFieldE[pgtype.Int8](v)
+	case pgtype.Float4:
+		res, err = SetFloatFieldE[pgtype.Float4](v)
+	case pgtype.Float8:
+		res, err = SetFloatFieldE[pgtype.Float8](v)
+	case pgtype.Bool:
+		res, err = SetBoolFieldE(v)
+	case pgtype.Date:
+		res, err = SetDateFieldE(v)
+	case pgtype.Timestamp:
+		res, err = SetTimestampFieldE(v)
+	case pgtype.Timestamptz:
+		res, err = SetTimestamptzFieldE(v)
+	case pgtype.UUID:
+		res, err = SetUUIDFieldE(v)
+	case pgtype.Numeric:
+		res, err = SetNumericFieldE(v)
+	case pgtype.Interval:
+		res, err = SetIntervalFieldE(v)
+	case pgtype.Time:
+		res, err = SetTimeFieldE(v)
+	case pgtype.Hstore:
+		res, err = SetHstoreFieldE(v)
+	case pgtype.Point:
+		res, err = SetPointFieldE(v)
+	case pgtype.Box:
+		res, err = SetBoxFieldE(v)
+	case []byte:
+		res, err = SetByteaFieldE(v)
+	case json.RawMessage:
+		res, err = SetJSONFieldE(v)
+	case netip.Prefix:
+		res, err = SetInetFieldE(v)
+	case net.HardwareAddr:
+		res, err = SetMacaddrFieldE(v)
+	}
+	if err != nil {
+		return out, err
+	}
+	return res.(T), nil
+}
+
+// Revert converts a pgtype value to the Go type G
+// Scalars revert like RevertArrayField elements, e.g. Revert[int32](pgtype.Int8) with an overflow check,
+// intervals to time.Duration, IntervalParts or string, times to time.Duration or string, points to LatLng,
+// boxes to BoundingBox, hstores to maps, json to any type it unmarshals into, inet to netip.Prefix, netip.Addr or string
+// NULL is returned as the zero G, use a pointer G to tell it apart
+// @param p P - The pgtype value to convert
+// @return G - The converted value
+// @return error - A *ConvertError if the value cannot be stored in G
+func Revert[G any, P PgType](p P) (G, error) {
+	var out G
+	if err := revertInto(p, reflect.ValueOf(&out).Elem()); err != nil {
+		var zero G
+		return zero, err
+	}
+	return out, nil
+}
+
+var (
+	durationType      = reflect.TypeOf(time.Duration(0))
+	intervalPartsType = reflect.TypeOf(IntervalParts{})
+	netipAddrType     = reflect.TypeOf(netip.Addr{})
+	hstoreMapType     = reflect.TypeOf(map[string]string(nil))
+)
+
+// revertInto converts a pgtype value into dst, extending revertScalarInto with the types that have no scalar Go value
+// @param src any - The pgtype value
+// @param dst reflect.Value - The settable destination
+// @return error - A *ConvertError if the value cannot be stored in dst
+func revertInto(src any, dst reflect.Value) error {
+	valid, ok := nonScalarValid(src)
+	if !ok {
+		return revertScalarInto(src, dst)
+	}
+	if reflect.TypeOf(src) == dst.Type() {
+		dst.Set(reflect.ValueOf(src))
+		return nil
+	}
+	if !valid {
+		dst.SetZero()
+		return nil
+	}
+	if dst.Kind() == reflect.Pointer {
+		elem := reflect.New(dst.Type().Elem())
+		if err := revertInto(src, elem.Elem()); err != nil {
+			return err
+		}
+		dst.Set(elem)
+		return nil
+	}
+
+	val, err := nonScalarValue(src, dst.Type())
+	if err != nil {
+		return revertError(src, dst, err)
+	}
+	rv := reflect.ValueOf(val)
+	switch {
+	case rv.Type().AssignableTo(dst.Type()):
+		dst.Set(rv)
+	case rv.Kind() == dst.Kind() && rv.Type().ConvertibleTo(dst.Type()):
+		dst.Set(rv.Convert(dst.Type()))
+	default:
+		return revertError(src, dst, ErrUnsupportedType)
+	}
+	return nil
+}
+
+// nonScalarValid reports whether a pgtype value that revertScalarInto does not handle is NULL
+// @param src any - The pgtype value
+// @return bool - False if the value is NULL
+// @return bool - False if the value is handled by revertScalarInto
+func nonScalarValid(src any) (bool, bool) {
+	switch s := src.(type) {
+	case pgtype.Interval:
+		return s.Valid, true
+	case pgtype.Time:
+		return s.Valid, true
+	case pgtype.Point:
+		return s.Valid, true
+	case pgtype.Box:
+		return s.Valid, true
+	case pgtype.Hstore:
+		return s != nil, true
+	case []byte:
+		return s != nil, true
+	case json.RawMessage:
+		return s != nil && string(s) != "null", true
+	case netip.Prefix:
+		return s.IsValid(), true
+	case net.HardwareAddr:
+		return s != nil, true
+	}
+	return false, false
+}
+
+// nonScalarValue returns the Go value of a valid pgtype value for the destination type
+// @param src any - The valid pgtype value
+// @param t reflect.Type - The destination type
+// @return any - The Go value
+// @return error - ErrUnsupportedType if the value has no conversion to t, or the conversion error
+func nonScalarValue(src any, t reflect.Type) (any, error) {
+	isString := t.Kind() == reflect.String
+	switch s := src.(type) {
+	case pgtype.Interval:
+		parts := IntervalParts{Months: s.Months, Days: s.Days, Microseconds: s.Microseconds}
+		switch {
+		case t == durationType:
+			return RevertPgInterval(s)
+		case t == intervalPartsType:
+			return parts, nil
+		case isString:
+			return parts.String(), nil
+		}
+	case pgtype.Time:
+		switch {
+		case t == durationType:
+			return RevertPgTimeDuration(s), nil
+		case isString:
+			return RevertPgTime(s, datecvx.Time_HHMMSS), nil
+		}
+	case pgtype.Point:
+		loc := RevertPgPoint(s)
+		if isString {
+			return strconv.FormatFloat(loc.Lat, 'f', -1, 64) + "," + strconv.FormatFloat(loc.Lng, 'f', -1, 64), nil
+		}
+		return loc, nil
+	case pgtype.Box:
+		return RevertPgBox(s), nil
+	case pgtype.Hstore:
+		switch {
+		case t == hstoreMapType:
+			return RevertPgHstore(s), nil
+		case isString:
+			return strconvx.FormatHstore(s), nil
+		}
+		return map[string]*string(s), nil
+	case []byte:
+		if isString {
+			return RevertPgByteaString(s, ByteaHex), nil
+		}
+	case json.RawMessage:
+		if isString {
+			return string(s), nil
+		}
+		out := reflect.New(t)
+		if err := json.Unmarshal(s, out.Interface()); err != nil {
+			return nil, err
+		}
+		return out.Elem().Interface(), nil
+	case netip.Prefix:
+		switch {
+		case t == netipAddrType:
+			return s.Addr(), nil
+		case isString:
+			return s.String(), nil
+		}
+		return s, nil
+	case net.HardwareAddr:
+		if isString {
+			return s.String(), nil
+		}
+	}
+	return nil, fmt.Errorf("%w: no conversion to %s", ErrUnsupportedType, t)
+}
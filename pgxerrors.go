@@ -0,0 +1,67 @@
+package pgxhelpers
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var (
+	// ErrUnsupportedType is returned when the input type has no conversion to the target pgtype
+	ErrUnsupportedType = errors.New("unsupported input type")
+	// ErrInvalidFormat is returned when a string input cannot be parsed into the target pgtype
+	ErrInvalidFormat = errors.New("invalid format")
+	// ErrOverflow is returned when the value does not fit in the range of the target pgtype
+	ErrOverflow = errors.New("value out of range")
+	// ErrFractional is returned when a value with a fractional part is converted to an integer type
+	ErrFractional = errors.New("value has a fractional part")
+	// ErrNonFinite is returned when NaN or infinity is converted to a type or with options that do not allow it
+	ErrNonFinite = errors.New("value is NaN or infinity")
+)
+
+// ConvertError describes why a value could not be converted to a pgtype
+// It's returned by the strict Set*FieldE functions, use errors.Is with the Err* values to check the cause
+// @field Input any - The value that was passed to the converter
+// @field Target string - The name of the target type, e.g. "pgtype.Date"
+// @field Err error - The cause of the failure
+type ConvertError struct {
+	Input  any
+	Target string
+	Err    error
+}
+
+// Error returns a message with the input, the target type and the cause
+// @return string - The error message
+func (e *ConvertError) Error() string {
+	input := e.Input
+	// show the pointed-to value instead of the address
+	if _, ok := input.(fmt.Stringer); !ok {
+		if rv := reflect.ValueOf(input); rv.Kind() == reflect.Pointer && !rv.IsNil() {
+			input = rv.Elem().Interface()
+		}
+	}
+	if s, ok := input.(string); ok {
+		return fmt.Sprintf("pgxhelpers: cannot convert %q to %s: %v", s, e.Target, e.Err)
+	}
+	return fmt.Sprintf("pgxhelpers: cannot convert %T(%v) to %s: %v", input, input, e.Target, e.Err)
+}
+
+// Unwrap returns the cause of the failure
+// @return error - The cause of the failure
+func (e *ConvertError) Unwrap() error {
+	return e.Err
+}
+
+// newConvertError creates a *ConvertError for the input and the target type T
+// @param input any - The value that was passed to the converter
+// @param err error - The cause of the failure
+// @return error - The *ConvertError
+func newConvertError[T any](input any, err error) error {
+	var target T
+	return &ConvertError{
+		Input:  input,
+		Target: strings.ReplaceAll(fmt.Sprintf("%T", target), "github.com/jackc/pgx/v5/", ""),
+		Err:    err,
+	}
+}
@@ -0,0 +1,273 @@
+package pgxhelpers
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/ChungNQ511/vnw-helpers/funcvx"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// EarthRadiusMeters is the mean Earth radius used by HaversineDistance and BoundingBoxAround
+const EarthRadiusMeters = 6371008.8
+
+// LatLng is a location in degrees
+// It's stored in a point column as (x, y) = (Lng, Lat)
+type LatLng struct {
+	Lat float64
+	Lng float64
+}
+
+// BoundingBox is a latitude/longitude rectangle
+// When Min.Lng is greater than Max.Lng the box crosses the antimeridian
+type BoundingBox struct {
+	Min LatLng
+	Max LatLng
+}
+
+// SetPointField sets a location to a pgtype.Point with X = longitude and Y = latitude
+// It accepts LatLng, [2]float64 and []float64 as (lat, lng), "lat,lng" strings and pgtype.Point
+// The latitude must be in [-90, 90] and the longitude in [-180, 180]
+// If the value is not supported, cannot be parsed or is out of range, it returns Valid=false
+// It's useful for converting store locations to a point column
+// @param v any - The value to convert to a pgtype.Point
+// @return pgtype.Point - The converted pgtype.Point
+func SetPointField(v any) pgtype.Point {
+	out, _ := SetPointFieldE(v)
+	return out
+}
+
+// SetPointFieldE is the strict variant of SetPointField
+// It returns a *ConvertError when the value is not supported, cannot be parsed (ErrInvalidFormat),
+// is NaN or infinity (ErrNonFinite) or is out of range (ErrOverflow)
+// @param v any - The value to convert to a pgtype.Point
+// @return pgtype.Point - The converted pgtype.Point
+// @return error - The conversion error, if any
+func SetPointFieldE(v any) (pgtype.Point, error) {
+	var (
+		loc LatLng
+		err error
+	)
+	switch val := v.(type) {
+	case nil:
+		return pgtype.Point{Valid: false}, nil
+	case pgtype.Point:
+		return val, nil
+	case LatLng:
+		loc = val
+	case *LatLng:
+		if val == nil {
+			return pgtype.Point{Valid: false}, nil
+		}
+		loc = *val
+	case [2]float64:
+		loc = LatLng{Lat: val[0], Lng: val[1]}
+	case []float64:
+		if val == nil {
+			return pgtype.Point{Valid: false}, nil
+		}
+		if len(val) != 2 {
+			return pgtype.Point{Valid: false}, newConvertError[pgtype.Point](v, fmt.Errorf("%w: expected 2 coordinates, got %d", ErrInvalidFormat, len(val)))
+		}
+		loc = LatLng{Lat: val[0], Lng: val[1]}
+	case string:
+		if !funcvx.NotNull(val) {
+			return pgtype.Point{Valid: false}, nil
+		}
+		loc, err = parseLatLng(val)
+	case *string:
+		if val == nil {
+			return pgtype.Point{Valid: false}, nil
+		}
+		return SetPointFieldE(*val)
+	default:
+		err = ErrUnsupportedType
+	}
+	if err == nil {
+		err = checkLatLng(loc)
+	}
+	if err != nil {
+		return pgtype.Point{Valid: false}, newConvertError[pgtype.Point](v, err)
+	}
+
+	return pgtype.Point{P: pgtype.Vec2{X: loc.Lng, Y: loc.Lat}, Valid: true}, nil
+}
+
+// SetBoxField sets a bounding box to a pgtype.Box with X = longitude and Y = latitude
+// It accepts BoundingBox and pgtype.Box, a box crossing the antimeridian cannot be stored and is rejected
+// If the value is not supported or out of range, it returns Valid=false
+// It's useful for storing delivery areas and map viewports
+// @param v any - The value to convert to a pgtype.Box
+// @return pgtype.Box - The converted pgtype.Box
+func SetBoxField(v any) pgtype.Box {
+	out, _ := SetBoxFieldE(v)
+	return out
+}
+
+// SetBoxFieldE is the strict variant of SetBoxField
+// It returns a *ConvertError when the value is not supported, out of range or crosses the antimeridian (ErrOverflow)
+// @param v any - The value to convert to a pgtype.Box
+// @return pgtype.Box - The converted pgtype.Box
+// @return error - The conversion error, if any
+func SetBoxFieldE(v any) (pgtype.Box, error) {
+	var box BoundingBox
+	switch val := v.(type) {
+	case nil:
+		return pgtype.Box{Valid: false}, nil
+	case pgtype.Box:
+		return val, nil
+	case BoundingBox:
+		box = val
+	case *BoundingBox:
+		if val == nil {
+			return pgtype.Box{Valid: false}, nil
+		}
+		box = *val
+	default:
+		return pgtype.Box{Valid: false}, newConvertError[pgtype.Box](v, ErrUnsupportedType)
+	}
+
+	err := checkLatLng(box.Min)
+	if err == nil {
+		err = checkLatLng(box.Max)
+	}
+	if err == nil && (box.Min.Lat > box.Max.Lat || box.Min.Lng > box.Max.Lng) {
+		err = fmt.Errorf("%w: min corner is above or east of max corner", ErrOverflow)
+	}
+	if err != nil {
+		return pgtype.Box{Valid: false}, newConvertError[pgtype.Box](v, err)
+	}
+
+	// PostgreSQL stores the upper right corner first
+	return pgtype.Box{
+		P: [2]pgtype.Vec2{
+			{X: box.Max.Lng, Y: box.Max.Lat},
+			{X: box.Min.Lng, Y: box.Min.Lat},
+		},
+		Valid: true,
+	}, nil
+}
+
+// HaversineDistance returns the great-circle distance between two locations in meters
+// It's useful for sorting and filtering nearby stores in memory
+// @param a LatLng - The first location
+// @param b LatLng - The second location
+// @return float64 - The distance in meters
+func HaversineDistance(a, b LatLng) float64 {
+	lat1 := a.Lat * math.Pi / 180
+	lat2 := b.Lat * math.Pi / 180
+	dLat := lat2 - lat1
+	dLng := (b.Lng - a.Lng) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * EarthRadiusMeters * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// BoundingBoxAround returns the smallest box containing every location within radius meters of center
+// Near the poles the box covers every longitude, across the antimeridian Min.Lng is greater than Max.Lng
+// It's useful as a cheap pre-filter before HaversineDistance, in memory or with a box column
+// @param center LatLng - The center of the search
+// @param radius float64 - The radius in meters, a negative or NaN radius is treated as 0
+// @return BoundingBox - The bounding box
+func BoundingBoxAround(center LatLng, radius float64) BoundingBox {
+	if !(radius >= 0) {
+		radius = 0
+	}
+	dLat := radius / EarthRadiusMeters * 180 / math.Pi
+	box := BoundingBox{
+		Min: LatLng{Lat: math.Max(center.Lat-dLat, -90), Lng: -180},
+		Max: LatLng{Lat: math.Min(center.Lat+dLat, 90), Lng: 180},
+	}
+	if box.Min.Lat == -90 || box.Max.Lat == 90 {
+		return box
+	}
+
+	// the widest longitude span is at the latitude where the circle touches the meridians
+	ratio := math.Sin(radius/EarthRadiusMeters) / math.Cos(center.Lat*math.Pi/180)
+	if ratio >= 1 {
+		return box
+	}
+	dLng := math.Asin(ratio) * 180 / math.Pi
+	box.Min.Lng = normalizeLng(center.Lng - dLng)
+	box.Max.Lng = normalizeLng(center.Lng + dLng)
+	return box
+}
+
+// Contains reports whether the location is inside the box, borders included
+// @param p LatLng - The location to check
+// @return bool - True if the location is inside the box
+func (b BoundingBox) Contains(p LatLng) bool {
+	if p.Lat < b.Min.Lat || p.Lat > b.Max.Lat {
+		return false
+	}
+	if b.Min.Lng <= b.Max.Lng {
+		return p.Lng >= b.Min.Lng && p.Lng <= b.Max.Lng
+	}
+	return p.Lng >= b.Min.Lng || p.Lng <= b.Max.Lng
+}
+
+// WithinRadius reports whether p is within radius meters of center
+// It checks the bounding box first and only computes the distance for locations inside it
+// @param center LatLng - The center of the search
+// @param p LatLng - The location to check
+// @param radius float64 - The radius in meters, a negative or NaN radius is treated as 0 so only center itself matches
+// @return bool - True if the location is within the radius
+func WithinRadius(center, p LatLng, radius float64) bool {
+	if !(radius >= 0) {
+		radius = 0
+	}
+	return BoundingBoxAround(center, radius).Contains(p) && HaversineDistance(center, p) <= radius
+}
+
+// parseLatLng parses a "lat,lng" string, whitespace around each number is ignored
+// The range of the values is not checked, use checkLatLng for that
+// @param s string - The string, e.g. "10.7769,106.7009"
+// @return LatLng - The parsed location
+// @return error - ErrInvalidFormat if the string is not two comma-separated numbers
+func parseLatLng(s string) (LatLng, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 2 {
+		return LatLng{}, fmt.Errorf(`%w: expected "lat,lng"`, ErrInvalidFormat)
+	}
+	lat, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil {
+		return LatLng{}, fmt.Errorf("%w: invalid latitude", ErrInvalidFormat)
+	}
+	lng, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err != nil {
+		return LatLng{}, fmt.Errorf("%w: invalid longitude", ErrInvalidFormat)
+	}
+	return LatLng{Lat: lat, Lng: lng}, nil
+}
+
+// checkLatLng checks that the location is finite and within the latitude and longitude ranges
+// @param l LatLng - The location to check
+// @return error - ErrNonFinite for NaN or infinite values, ErrOverflow if a value is out of range, nil otherwise
+func checkLatLng(l LatLng) error {
+	if math.IsNaN(l.Lat) || math.IsInf(l.Lat, 0) || math.IsNaN(l.Lng) || math.IsInf(l.Lng, 0) {
+		return ErrNonFinite
+	}
+	if l.Lat < -90 || l.Lat > 90 {
+		return fmt.Errorf("%w: latitude %v is not in [-90, 90]", ErrOverflow, l.Lat)
+	}
+	if l.Lng < -180 || l.Lng > 180 {
+		return fmt.Errorf("%w: longitude %v is not in [-180, 180]", ErrOverflow, l.Lng)
+	}
+	return nil
+}
+
+// normalizeLng wraps a longitude into [-180, 180]
+// It wraps by one turn only, which is enough for a valid longitude offset by less than 360 degrees
+// @param lng float64 - The longitude
+// @return float64 - The wrapped longitude
+func normalizeLng(lng float64) float64 {
+	if lng < -180 {
+		return lng + 360
+	}
+	if lng > 180 {
+		return lng - 360
+	}
+	return lng
+}
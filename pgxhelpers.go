@@ -1,8 +1,13 @@
 package pgxhelpers
 
 import (
+	"errors"
 	"fmt"
+	"math"
 	"math/big"
+	"reflect"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -17,28 +22,65 @@ import (
 // @param s any - The value to convert to a pgtype.Text
 // @return pgtype.Text - The converted pgtype.Text
 func SetTextField(v any) pgtype.Text {
+	out, _ := SetTextFieldE(v)
+	return out
+}
+
+// SetTextFieldE is the strict variant of SetTextField
+// It returns a *ConvertError instead of an invalid pgtype.Text when the value is not supported
+// An empty string, empty []byte or nil is not an error, it returns a pgtype.Text with false
+// It uses the default policy, see SetDefaultPolicy
+// @param v any - The value to convert to a pgtype.Text
+// @return pgtype.Text - The converted pgtype.Text
+// @return error - The conversion error, if any
+func SetTextFieldE(v any) (pgtype.Text, error) {
+	return DefaultConverter().SetTextFieldE(v)
+}
+
+// SetTextField is SetTextField with the rules of the Converter
+// @param v any - The value to convert to a pgtype.Text
+// @return pgtype.Text - The converted pgtype.Text
+func (c *Converter) SetTextField(v any) pgtype.Text {
+	out, _ := c.SetTextFieldE(v)
+	return out
+}
+
+// SetTextFieldE is SetTextFieldE with the rules of the Converter
+// Strings are trimmed if Trim is set, empty strings are NULL only if EmptyAsNull is set
+// @param v any - The value to convert to a pgtype.Text
+// @return pgtype.Text - The converted pgtype.Text
+// @return error - The conversion error, if any
+func (c *Converter) SetTextFieldE(v any) (pgtype.Text, error) {
 	switch val := v.(type) {
+	case nil:
+		return pgtype.Text{}, nil
 	case string:
-		return pgtype.Text{
-			String: val,
-			Valid:  val != "",
+		return c.text(val), nil
+	case *string:
+		if val == nil {
+			return pgtype.Text{}, nil
 		}
+		return c.SetTextFieldE(*val)
 	case []byte:
-		return pgtype.Text{
-			String: string(val),
-			Valid:  len(val) > 0,
+		if val == nil {
+			return pgtype.Text{}, nil
 		}
+		return c.text(string(val)), nil
 	case fmt.Stringer:
-		s := val.String()
-		return pgtype.Text{
-			String: s,
-			Valid:  s != "",
-		}
+		return c.text(val.String()), nil
 	default:
-		return pgtype.Text{
-			String: "",
-			Valid:  false,
-		}
+		return pgtype.Text{}, newConvertError[pgtype.Text](v, ErrUnsupportedType)
+	}
+}
+
+// text applies the Trim and EmptyAsNull rules to a string
+// @param s string - The string
+// @return pgtype.Text - The converted pgtype.Text
+func (c *Converter) text(s string) pgtype.Text {
+	s = c.policy.trim(s)
+	return pgtype.Text{
+		String: s,
+		Valid:  s != "" || !c.policy.EmptyAsNull,
 	}
 }
 
@@ -49,22 +91,38 @@ func SetTextField(v any) pgtype.Text {
 // @param v any - The value to convert to a pgtype.Float4 or pgtype.Float8
 // @return T - The converted pgtype.Float4 or pgtype.Float8
 func SetFloatField[T pgtype.Float4 | pgtype.Float8](v any) T {
+	out, _ := SetFloatFieldE[T](v)
+	return out
+}
+
+// SetFloatFieldE is the strict variant of SetFloatField
+// It returns a *ConvertError instead of an invalid pgtype.Float4 or pgtype.Float8 when the value is not supported
+// A nil value or nil pointer is not an error, it returns a pgtype.Float4 or pgtype.Float8 with false
+// @param v any - The value to convert to a pgtype.Float4 or pgtype.Float8
+// @return T - The converted pgtype.Float4 or pgtype.Float8
+// @return error - The conversion error, if any
+func SetFloatFieldE[T pgtype.Float4 | pgtype.Float8](v any) (T, error) {
 	var out T
 
 	switch val := v.(type) {
+	case nil:
 	case float32:
 		out = setFloatToPG[T](float64(val))
 	case float64:
 		out = setFloatToPG[T](val)
+	case *float32:
+		if val != nil {
+			out = setFloatToPG[T](float64(*val))
+		}
 	case *float64:
 		if val != nil {
 			out = setFloatToPG[T](*val)
 		}
 	default:
-		// fallback: Valid=false
+		return out, newConvertError[T](v, ErrUnsupportedType)
 	}
 
-	return out
+	return out, nil
 }
 
 // setFloatToPG sets a float64 to a pgtype.Float4 or pgtype.Float8
@@ -90,45 +148,146 @@ func setFloatToPG[T pgtype.Float4 | pgtype.Float8](val float64) T {
 	return out
 }
 
-// SetIntField sets an int or int32 or int64 to a pgtype.Int2 or pgtype.Int4 or pgtype.Int8
-// It returns a pgtype.Int2 or pgtype.Int4 or pgtype.Int8 with the int or int32 or int64 value and a boolean indicating if the value is valid
-// If the value is not an int or int32 or int64, it returns a pgtype.Int2 or pgtype.Int4 or pgtype.Int8 with a 0 and false
-// It's useful for converting an int or int32 or int64 to a pgtype.Int2 or pgtype.Int4 or pgtype.Int8
+// SetIntField sets any Go integer, a pointer to one, a numeric string or a pgtype.Numeric to a pgtype.Int2 or pgtype.Int4 or pgtype.Int8
+// It returns a pgtype.Int2 or pgtype.Int4 or pgtype.Int8 with the integer value and a boolean indicating if the value is valid
+// If the value is not supported or does not fit in the target width, it returns a pgtype.Int2 or pgtype.Int4 or pgtype.Int8 with a 0 and false
+// It's useful for converting an integer to a pgtype.Int2 or pgtype.Int4 or pgtype.Int8
 // @param v any - The value to convert to a pgtype.Int2 or pgtype.Int4 or pgtype.Int8
 // @return T - The converted pgtype.Int2 or pgtype.Int4 or pgtype.Int8
 func SetIntField[T pgtype.Int2 | pgtype.Int4 | pgtype.Int8](v any) T {
+	out, _ := SetIntFieldE[T](v)
+	return out
+}
+
+// SetIntFieldE is the strict variant of SetIntField
+// It returns a *ConvertError instead of an invalid pgtype.Int2 or pgtype.Int4 or pgtype.Int8 when the value is not supported,
+// the string is not an integer or the value does not fit in the target width (ErrOverflow)
+// A nil value, nil pointer or null string is not an error, it returns a pgtype.Int2 or pgtype.Int4 or pgtype.Int8 with false
+// @param v any - The value to convert to a pgtype.Int2 or pgtype.Int4 or pgtype.Int8
+// @return T - The converted pgtype.Int2 or pgtype.Int4 or pgtype.Int8
+// @return error - The conversion error, if any
+func SetIntFieldE[T pgtype.Int2 | pgtype.Int4 | pgtype.Int8](v any) (T, error) {
 	var out T
 
+	n, valid, err := intFromAny(v)
+	if err != nil {
+		return out, newConvertError[T](v, err)
+	}
+	if !valid {
+		return out, nil
+	}
+
+	out, err = setIntToPG[T](n)
+	if err != nil {
+		return out, newConvertError[T](v, err)
+	}
+	return out, nil
+}
+
+// intFromAny extracts an int64 from any Go integer, a pointer to one, a numeric string or a pgtype integer or numeric
+// It returns false for nil, nil pointers, null strings and invalid pgtypes
+// @param v any - The value to extract the int64 from
+// @return int64 - The extracted int64
+// @return bool - True if the value is not null
+// @return error - ErrUnsupportedType, ErrInvalidFormat, ErrFractional or ErrOverflow
+func intFromAny(v any) (int64, bool, error) {
 	switch val := v.(type) {
+	case nil:
+		return 0, false, nil
 	case int:
-		out = setIntToPG[T](int64(val))
+		return int64(val), true, nil
+	case int8:
+		return int64(val), true, nil
+	case int16:
+		return int64(val), true, nil
 	case int32:
-		out = setIntToPG[T](int64(val))
+		return int64(val), true, nil
 	case int64:
-		out = setIntToPG[T](val)
+		return val, true, nil
+	case uint:
+		return uintToInt64(uint64(val))
+	case uint8:
+		return int64(val), true, nil
+	case uint16:
+		return int64(val), true, nil
+	case uint32:
+		return int64(val), true, nil
+	case uint64:
+		return uintToInt64(val)
+	case *int, *int8, *int16, *int32, *int64, *uint, *uint8, *uint16, *uint32, *uint64, *string:
+		rv := reflect.ValueOf(val)
+		if rv.IsNil() {
+			return 0, false, nil
+		}
+		return intFromAny(rv.Elem().Interface())
+	case string:
+		if !funcvx.NotNull(val) {
+			return 0, false, nil
+		}
+		n, err := strconv.ParseInt(strings.TrimSpace(val), 10, 64)
+		if err != nil {
+			if errors.Is(err, strconv.ErrRange) {
+				return 0, false, ErrOverflow
+			}
+			return 0, false, fmt.Errorf("%w: not an integer", ErrInvalidFormat)
+		}
+		return n, true, nil
+	case pgtype.Int2:
+		return int64(val.Int16), val.Valid, nil
+	case pgtype.Int4:
+		return int64(val.Int32), val.Valid, nil
+	case pgtype.Int8:
+		return val.Int64, val.Valid, nil
+	case pgtype.Numeric:
+		if !val.Valid {
+			return 0, false, nil
+		}
+		n, err := numericToInt64(val)
+		if err != nil {
+			return 0, false, err
+		}
+		return n, true, nil
 	default:
-		// Trả về zero value với Valid=false
-		return out
+		return 0, false, ErrUnsupportedType
 	}
-	return out
+}
+
+// uintToInt64 converts a uint64 to an int64
+// It returns ErrOverflow if the value is greater than math.MaxInt64
+// @param val uint64 - The value to convert
+// @return int64 - The converted int64
+// @return bool - Always true when there is no error
+// @return error - ErrOverflow if the value does not fit
+func uintToInt64(val uint64) (int64, bool, error) {
+	if val > math.MaxInt64 {
+		return 0, false, ErrOverflow
+	}
+	return int64(val), true, nil
 }
 
 // setIntToPG sets an int64 to a pgtype.Int2 or pgtype.Int4 or pgtype.Int8
 // It returns a pgtype.Int2 or pgtype.Int4 or pgtype.Int8 with the int64 value and a boolean indicating if the value is valid
-// If the value is not an int64, it returns a pgtype.Int2 or pgtype.Int4 or pgtype.Int8 with a 0 and false
+// If the value does not fit in the target width, it returns a pgtype.Int2 or pgtype.Int4 or pgtype.Int8 with a 0 and false and ErrOverflow
 // It's useful for converting an int64 to a pgtype.Int2 or pgtype.Int4 or pgtype.Int8
 // @param val int64 - The value to convert to a pgtype.Int2 or pgtype.Int4 or pgtype.Int8
 // @return T - The converted pgtype.Int2 or pgtype.Int4 or pgtype.Int8
-func setIntToPG[T pgtype.Int2 | pgtype.Int4 | pgtype.Int8](val int64) T {
+// @return error - ErrOverflow if the value does not fit
+func setIntToPG[T pgtype.Int2 | pgtype.Int4 | pgtype.Int8](val int64) (T, error) {
 	var out T
 
 	switch any(out).(type) {
 	case pgtype.Int2:
+		if val < math.MinInt16 || val > math.MaxInt16 {
+			return out, fmt.Errorf("%w: %d does not fit in int2", ErrOverflow, val)
+		}
 		out = any(pgtype.Int2{
 			Int16: int16(val),
 			Valid: true,
 		}).(T)
 	case pgtype.Int4:
+		if val < math.MinInt32 || val > math.MaxInt32 {
+			return out, fmt.Errorf("%w: %d does not fit in int4", ErrOverflow, val)
+		}
 		out = any(pgtype.Int4{
 			Int32: int32(val),
 			Valid: true,
@@ -140,7 +299,7 @@ func setIntToPG[T pgtype.Int2 | pgtype.Int4 | pgtype.Int8](val int64) T {
 		}).(T)
 	}
 
-	return out
+	return out, nil
 }
 
 // SetDateField sets a time.Time or *time.Time or string to a pgtype.Date
@@ -150,17 +309,49 @@ func setIntToPG[T pgtype.Int2 | pgtype.Int4 | pgtype.Int8](val int64) T {
 // @param v any - The value to convert to a pgtype.Date
 // @return pgtype.Date - The converted pgtype.Date
 func SetDateField(v any) pgtype.Date {
+	out, _ := SetDateFieldE(v)
+	return out
+}
+
+// SetDateFieldE is the strict variant of SetDateField
+// It returns a *ConvertError instead of an invalid pgtype.Date when the value is not supported or the string cannot be parsed
+// A nil value, nil pointer or null string is not an error, it returns a pgtype.Date with false
+// It uses the default policy, see SetDefaultPolicy
+// @param v any - The value to convert to a pgtype.Date
+// @return pgtype.Date - The converted pgtype.Date
+// @return error - The conversion error, if any
+func SetDateFieldE(v any) (pgtype.Date, error) {
+	return DefaultConverter().SetDateFieldE(v)
+}
+
+// SetDateField is SetDateField with the rules of the Converter
+// @param v any - The value to convert to a pgtype.Date
+// @return pgtype.Date - The converted pgtype.Date
+func (c *Converter) SetDateField(v any) pgtype.Date {
+	out, _ := c.SetDateFieldE(v)
+	return out
+}
+
+// SetDateFieldE is SetDateFieldE with the rules of the Converter
+// Strings are parsed with the layouts of the policy, see LayoutRegistry
+// @param v any - The value to convert to a pgtype.Date
+// @return pgtype.Date - The converted pgtype.Date
+// @return error - The conversion error, if any
+func (c *Converter) SetDateFieldE(v any) (pgtype.Date, error) {
 	switch val := v.(type) {
+	case nil:
 	case time.Time:
-		return pgtype.Date{Time: val, Valid: true}
+		return pgtype.Date{Time: val, Valid: true}, nil
 	case *time.Time:
 		if val != nil {
-			return pgtype.Date{Time: *val, Valid: true}
+			return pgtype.Date{Time: *val, Valid: true}, nil
 		}
 	case string:
-		return stringToPgDate(val)
+		return c.stringToPgDate(val)
+	default:
+		return pgtype.Date{}, newConvertError[pgtype.Date](v, ErrUnsupportedType)
 	}
-	return pgtype.Date{Valid: false}
+	return pgtype.Date{Valid: false}, nil
 }
 
 // SetTimestampField sets a time.Time or *time.Time or string to a pgtype.Timestamp
@@ -170,114 +361,96 @@ func SetDateField(v any) pgtype.Date {
 // @param v any - The value to convert to a pgtype.Timestamp
 // @return pgtype.Timestamp - The converted pgtype.Timestamp
 func SetTimestampField(v any) pgtype.Timestamp {
+	out, _ := SetTimestampFieldE(v)
+	return out
+}
+
+// SetTimestampFieldE is the strict variant of SetTimestampField
+// It returns a *ConvertError instead of an invalid pgtype.Timestamp when the value is not supported or the string cannot be parsed
+// A nil value, nil pointer or null string is not an error, it returns a pgtype.Timestamp with false
+// It uses the default policy, see SetDefaultPolicy
+// @param v any - The value to convert to a pgtype.Timestamp
+// @return pgtype.Timestamp - The converted pgtype.Timestamp
+// @return error - The conversion error, if any
+func SetTimestampFieldE(v any) (pgtype.Timestamp, error) {
+	return DefaultConverter().SetTimestampFieldE(v)
+}
+
+// SetTimestampField is SetTimestampField with the rules of the Converter
+// @param v any - The value to convert to a pgtype.Timestamp
+// @return pgtype.Timestamp - The converted pgtype.Timestamp
+func (c *Converter) SetTimestampField(v any) pgtype.Timestamp {
+	out, _ := c.SetTimestampFieldE(v)
+	return out
+}
+
+// SetTimestampFieldE is SetTimestampFieldE with the rules of the Converter
+// Strings are parsed with the layouts of the policy, see LayoutRegistry, two-digit years are expanded with YearPivot
+// @param v any - The value to convert to a pgtype.Timestamp
+// @return pgtype.Timestamp - The converted pgtype.Timestamp
+// @return error - The conversion error, if any
+func (c *Converter) SetTimestampFieldE(v any) (pgtype.Timestamp, error) {
 	switch val := v.(type) {
+	case nil:
 	case time.Time:
-		return pgtype.Timestamp{Time: val, Valid: true}
+		return pgtype.Timestamp{Time: val, Valid: true}, nil
 	case *time.Time:
 		if val != nil {
-			return pgtype.Timestamp{Time: *val, Valid: true}
+			return pgtype.Timestamp{Time: *val, Valid: true}, nil
 		}
 	case string:
-		return stringToPgTimestamp(val)
+		return c.stringToPgTimestamp(val)
+	default:
+		return pgtype.Timestamp{}, newConvertError[pgtype.Timestamp](v, ErrUnsupportedType)
 	}
-	return pgtype.Timestamp{Valid: false}
+	return pgtype.Timestamp{Valid: false}, nil
 }
 
-// StringToPgDate converts a string to a pgtype.Date
+// stringToPgDate converts a string to a pgtype.Date
 // It returns a pgtype.Date with the string value and a boolean indicating if the value is valid
 // If the value is not a string, it returns a pgtype.Date with a zero time and false
 // It's useful for converting a string to a pgtype.Date
 // @param s string - The value to convert to a pgtype.Date
 // @return pgtype.Date - The converted pgtype.Date
-func stringToPgDate(s string) pgtype.Date {
+// @return error - A *ConvertError if no layout matches
+func (c *Converter) stringToPgDate(s string) (pgtype.Date, error) {
+	input := c.policy.trim(s)
 	// check null, nil ,...
-	if !funcvx.NotNull(s) {
-		return pgtype.Date{}
-	}
-
-	// accept formats
-	formats := []string{
-		"2006-01-02",          // ISO format
-		"2/1/2006",            // d/m/yyyy
-		"2/1/2006 15:04:05",   // d/m/yyyy H:M:S
-		"01/02/2006",          // mm/dd/yyyy (nếu có data kiểu Mỹ)
-		"2006-01-02 15:04:05", // ISO datetime
-		"2006/01/02",          // yyyy/mm/dd
+	if !funcvx.NotNull(input) {
+		return pgtype.Date{}, nil
 	}
 
-	for _, format := range formats {
-		if date, err := time.Parse(format, s); err == nil {
-			return pgtype.Date{
-				Time:  date,
-				Valid: true,
-			}
-		}
+	date, _, err := c.parseTime(input, time.UTC)
+	if err != nil {
+		return pgtype.Date{}, newConvertError[pgtype.Date](s, err)
 	}
-
-	// fallback if not parsed
-	return pgtype.Date{}
+	return pgtype.Date{
+		Time:  date,
+		Valid: true,
+	}, nil
 }
 
 // stringToPgTimestamp converts a string to a pgtype.Timestamp
 // It returns a pgtype.Timestamp with the string value and a boolean indicating if the value is valid
 // If the value is not a string, it returns a pgtype.Timestamp with a zero time and false
 // It's useful for converting a string to a pgtype.Timestamp
-// @param input string - The value to convert to a pgtype.Timestamp
+// @param s string - The value to convert to a pgtype.Timestamp
 // @return pgtype.Timestamp - The converted pgtype.Timestamp
-func stringToPgTimestamp(input string) pgtype.Timestamp {
+// @return error - A *ConvertError if no layout matches
+func (c *Converter) stringToPgTimestamp(s string) (pgtype.Timestamp, error) {
+	input := c.policy.trim(s)
 	if !funcvx.NotNull(input) {
 		return pgtype.Timestamp{
 			Time:  time.Time{},
 			Valid: false,
-		}
-	}
-
-	input = strings.ReplaceAll(input, "/", "-")
-
-	parts := strings.Split(input, " ")
-	if len(parts) < 2 {
-		// auto add hour if missing
-		parts = append(parts, "00:00:00.000000")
+		}, nil
 	}
 
-	// auto add hour if missing
-	dayParts := strings.Split(parts[0], "-")
-	if len(dayParts) != 3 {
-		return pgtype.Timestamp{}
-	}
-	// zero-padding
-	for i := 0; i < 2; i++ {
-		if len(dayParts[i]) == 1 {
-			dayParts[i] = "0" + dayParts[i]
-		}
-	}
-	if len(dayParts[2]) == 2 { // 24 -> 2024
-		dayParts[2] = "20" + dayParts[2]
-	}
-	date := fmt.Sprintf("%s-%s-%s", dayParts[2], dayParts[1], dayParts[0]) // yyyy-mm-dd
-
-	// auto add microsecond if missing
-	timePart := parts[1]
-	if !strings.Contains(timePart, ".") {
-		timePart += ".000000"
-	} else {
-		// pad microsecond if missing
-		tp := strings.Split(timePart, ".")
-		for len(tp[1]) < 6 {
-			tp[1] += "0"
-		}
-		timePart = tp[0] + "." + tp[1][:6]
-	}
-
-	final := fmt.Sprintf("%s %s", date, timePart)
-
-	// parse
-	t, err := time.Parse("2006-01-02 15:04:05.000000", final)
+	t, _, err := c.parseTime(input, time.UTC)
 	if err != nil {
-		return pgtype.Timestamp{}
+		return pgtype.Timestamp{}, newConvertError[pgtype.Timestamp](s, err)
 	}
-
-	return pgtype.Timestamp{Time: t, Valid: true}
+	return pgtype.Timestamp{Time: t, Valid: true}, nil
 }
 
 // PgBool converts a string to a pgtype.Bool
@@ -319,181 +492,321 @@ func PgBool(s string) pgtype.Bool {
 // @param v any - The value to convert to a pgtype.Timestamptz
 // @return pgtype.Timestamptz - The converted pgtype.Timestamptz
 func SetTimestamptzField(v any) pgtype.Timestamptz {
+	out, _ := SetTimestamptzFieldE(v)
+	return out
+}
+
+// SetTimestamptzFieldE is the strict variant of SetTimestamptzField
+// It returns a *ConvertError instead of an invalid pgtype.Timestamptz when the value is not supported or the string cannot be parsed
+// A nil value, nil pointer or null string is not an error, it returns a pgtype.Timestamptz with false
+// It uses the default policy, see SetDefaultPolicy
+// @param v any - The value to convert to a pgtype.Timestamptz
+// @return pgtype.Timestamptz - The converted pgtype.Timestamptz
+// @return error - The conversion error, if any
+func SetTimestamptzFieldE(v any) (pgtype.Timestamptz, error) {
+	return DefaultConverter().SetTimestamptzFieldE(v)
+}
+
+// SetTimestamptzField is SetTimestamptzField with the rules of the Converter
+// @param v any - The value to convert to a pgtype.Timestamptz
+// @return pgtype.Timestamptz - The converted pgtype.Timestamptz
+func (c *Converter) SetTimestamptzField(v any) pgtype.Timestamptz {
+	out, _ := c.SetTimestamptzFieldE(v)
+	return out
+}
+
+// SetTimestamptzFieldE is SetTimestamptzFieldE with the rules of the Converter
+// Strings are parsed with the layouts of the policy, see LayoutRegistry, and may end with a UTC offset or an IANA zone name
+// Zone-less strings are read in the Location of the policy and results are converted to UTC if NormalizeUTC is set
+// @param v any - The value to convert to a pgtype.Timestamptz
+// @return pgtype.Timestamptz - The converted pgtype.Timestamptz
+// @return error - The conversion error, if any
+func (c *Converter) SetTimestamptzFieldE(v any) (pgtype.Timestamptz, error) {
 	switch val := v.(type) {
+	case nil:
 	case time.Time:
-		return pgtype.Timestamptz{Time: val.UTC(), Valid: true}
+		return pgtype.Timestamptz{Time: c.normalize(val), Valid: true}, nil
 	case *time.Time:
 		if val != nil {
-			return pgtype.Timestamptz{Time: val.UTC(), Valid: true}
+			return pgtype.Timestamptz{Time: c.normalize(*val), Valid: true}, nil
 		}
 	case string:
-		layouts := []string{
-			time.RFC3339,          // e.g. "2025-06-17T15:04:05Z"
-			"2006-01-02 15:04:05", // e.g. "2025-06-17 14:00:00"
-			"2006-01-02",          // fallback: "2025-06-17" → time 00:00:00
-		}
-		for _, layout := range layouts {
-			if t, err := time.Parse(layout, val); err == nil {
-				return pgtype.Timestamptz{Time: t.UTC(), Valid: true}
-			}
-		}
+		return c.stringToPgTimestamptz(val)
+	default:
+		return pgtype.Timestamptz{}, newConvertError[pgtype.Timestamptz](v, ErrUnsupportedType)
+	}
+	return pgtype.Timestamptz{Valid: false}, nil
+}
+
+// normalize converts a time to UTC if NormalizeUTC is set
+// @param t time.Time - The time
+// @return time.Time - The time, in UTC or as is
+func (c *Converter) normalize(t time.Time) time.Time {
+	if c.policy.NormalizeUTC {
+		return t.UTC()
 	}
-	return pgtype.Timestamptz{Valid: false}
+	return t
 }
 
-func stringToPgTimestamptz(input string) pgtype.Timestamptz {
+// stringToPgTimestamptz converts a string to a pgtype.Timestamptz
+// The string may end with a UTC offset after the clock, e.g. "+07", "+07:00", "+0700" or "Z",
+// or an IANA zone name, e.g. " Asia/Ho_Chi_Minh" or "[Asia/Ho_Chi_Minh]", zone-less strings are in the policy location
+// The rest is parsed with the layouts of the policy, so "15/01/2024 14:30" works like in SetTimestampField
+// @param s string - The value to convert to a pgtype.Timestamptz
+// @return pgtype.Timestamptz - The converted pgtype.Timestamptz
+// @return error - A *ConvertError if the zone is unknown or no layout matches
+func (c *Converter) stringToPgTimestamptz(s string) (pgtype.Timestamptz, error) {
+	input := c.policy.trim(s)
 	// check nil| null | ""
 	if !funcvx.NotNull(input) {
-		return pgtype.Timestamptz{
-			Time:  time.Time{},
-			Valid: false,
-		}
+		return pgtype.Timestamptz{}, nil
 	}
 
-	input = strings.ReplaceAll(input, "/", "-")
-
-	parts := strings.Split(input, " ")
-	if len(parts) < 2 {
-		parts = append(parts, "00:00:00.000000")
+	input, loc, zone, err := splitTimeZone(input, c.policy.location())
+	if err != nil {
+		return pgtype.Timestamptz{}, newConvertError[pgtype.Timestamptz](s, err)
 	}
-
-	dayParts := strings.Split(parts[0], "-")
-	if len(dayParts) != 3 {
-		return pgtype.Timestamptz{}
+	t, _, err := c.parseTime(input, loc)
+	if err != nil {
+		return pgtype.Timestamptz{}, newConvertError[pgtype.Timestamptz](s, err)
 	}
-	// zero-padding
-	for i := 0; i < 2; i++ {
-		if len(dayParts[i]) == 1 {
-			dayParts[i] = "0" + dayParts[i]
-		}
+	if zone != nil {
+		t = t.In(zone)
 	}
-	if len(dayParts[2]) == 2 { // 24 -> 2024
-		dayParts[2] = "20" + dayParts[2]
+	return pgtype.Timestamptz{Time: c.normalize(t), Valid: true}, nil
+}
+
+var (
+	// zoneNameSuffix matches an IANA zone name at the end of a string, e.g. " Asia/Ho_Chi_Minh" or "[Asia/Ho_Chi_Minh]"
+	zoneNameSuffix = regexp.MustCompile(`(?:\[([A-Za-z][\w+-]*(?:/[\w+-]+)*)\]|\s([A-Za-z][\w+-]*(?:/[\w+-]+)+|UTC|GMT))$`)
+	// zoneOffsetSuffix matches a UTC offset after the clock, e.g. "14:30:00+07", "14:30:00.5 +07:00" or "14:30Z"
+	zoneOffsetSuffix = regexp.MustCompile(`\d:\d{2}(?::\d{2}(?:\.\d+)?)?\s*(Z|UTC|GMT|([+-])(\d{1,2})(?::?(\d{2}))?)$`)
+)
+
+// splitTimeZone removes a trailing zone name and UTC offset from a time string
+// @param s string - The time string
+// @param def *time.Location - The location used when the string has no zone
+// @return string - The string without the zone
+// @return *time.Location - The location to parse the rest in, a fixed zone for offsets
+// @return *time.Location - The named zone to convert the result to, nil if the string has no zone name
+// @return error - ErrInvalidFormat if the zone name is unknown or the offset is out of range
+func splitTimeZone(s string, def *time.Location) (string, *time.Location, *time.Location, error) {
+	loc := def
+	var zone *time.Location
+	if m := zoneNameSuffix.FindStringSubmatchIndex(s); m != nil {
+		start, end := m[2], m[3]
+		if start < 0 { // the name is not in brackets
+			start, end = m[4], m[5]
+		}
+		name := s[start:end]
+		var err error
+		if zone, err = time.LoadLocation(name); err != nil || name == "Local" {
+			return "", nil, nil, fmt.Errorf("%w: unknown time zone %q", ErrInvalidFormat, name)
+		}
+		loc = zone
+		s = strings.TrimRight(s[:m[0]], " ")
 	}
-	date := fmt.Sprintf("%s-%s-%s", dayParts[2], dayParts[1], dayParts[0]) // yyyy-mm-dd
 
-	timePart := parts[1]
-	if !strings.Contains(timePart, ".") {
-		timePart += ".000000"
+	m := zoneOffsetSuffix.FindStringSubmatchIndex(s)
+	if m == nil {
+		return s, loc, zone, nil
+	}
+	if m[4] < 0 { // Z, UTC or GMT
+		loc = time.UTC
 	} else {
-		// pad microsecond if missing
-		tp := strings.Split(timePart, ".")
-		for len(tp[1]) < 6 {
-			tp[1] += "0"
+		hours, _ := strconv.Atoi(s[m[6]:m[7]])
+		minutes := 0
+		if m[8] >= 0 {
+			minutes, _ = strconv.Atoi(s[m[8]:m[9]])
 		}
-		timePart = tp[0] + "." + tp[1][:6]
-	}
-
-	final := fmt.Sprintf("%s %s", date, timePart)
-
-	// parse
-	t, err := time.Parse("2006-01-02 15:04:05.000000", final)
-	if err != nil {
-		return pgtype.Timestamptz{}
+		if hours > 15 || minutes > 59 {
+			return "", nil, nil, fmt.Errorf("%w: UTC offset %q out of range", ErrInvalidFormat, s[m[2]:m[3]])
+		}
+		offset := (hours*60 + minutes) * 60
+		if s[m[4]:m[5]] == "-" {
+			offset = -offset
+		}
+		loc = time.FixedZone("", offset)
 	}
+	return strings.TrimRight(s[:m[2]], " "), loc, zone, nil
+}
 
-	return pgtype.Timestamptz{Time: t, Valid: true}
+// SetNumericField sets a decimal string, any Go integer or float, a *big.Int, *big.Rat or *big.Float to a pgtype.Numeric
+// It returns a pgtype.Numeric with the exact decimal value and a boolean indicating if the value is valid
+// If the value is not supported, cannot be parsed or is rejected by the options, it returns a pgtype.Numeric with a 0 and false
+// It's useful for converting money and quantities to a pgtype.Numeric without binary rounding errors
+// @param i any - The value to convert to a pgtype.Numeric
+// @param opts ...NumericOption - Scale, precision and NaN/infinity options
+// @return pgtype.Numeric - The converted pgtype.Numeric
+func SetNumericField(i any, opts ...NumericOption) pgtype.Numeric {
+	out, _ := SetNumericFieldE(i, opts...)
+	return out
 }
 
-// SetNumericField sets an int or int32 or int64 or float64 or float32 to a pgtype.Numeric
-// It returns a pgtype.Numeric with the int or int32 or int64 or float64 or float32 value and a boolean indicating if the value is valid
-// If the value is not an int or int32 or int64 or float64 or float32, it returns a pgtype.Numeric with a 0 and false
-// It's useful for converting an int or int32 or int64 or float64 or float32 to a pgtype.Numeric
+// SetNumericFieldE is the strict variant of SetNumericField
+// It returns a *ConvertError instead of an invalid pgtype.Numeric when the value is not supported or cannot be parsed,
+// when it is NaN or infinity and not allowed (ErrNonFinite) or when it does not fit the precision (ErrOverflow)
+// A nil value, nil pointer or null string is not an error, it returns a pgtype.Numeric with false
 // @param i any - The value to convert to a pgtype.Numeric
+// @param opts ...NumericOption - Scale, precision and NaN/infinity options
 // @return pgtype.Numeric - The converted pgtype.Numeric
-func SetNumericField(i any) pgtype.Numeric {
+// @return error - The conversion error, if any
+func SetNumericFieldE(i any, opts ...NumericOption) (pgtype.Numeric, error) {
+	var cfg numericConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	var (
+		num pgtype.Numeric
+		err error
+	)
 	switch res := i.(type) {
-	case int:
-		return pgtype.Numeric{
-			Int:   big.NewInt(int64(res)),
-			Exp:   0,
-			Valid: true,
-		}
-	case int32:
-		return pgtype.Numeric{
-			Int:   big.NewInt(int64(res)),
-			Exp:   0,
-			Valid: true,
+	case nil:
+		return pgtype.Numeric{}, nil
+	case string:
+		if !funcvx.NotNull(res) {
+			return pgtype.Numeric{}, nil
 		}
-	case int64:
-		return pgtype.Numeric{
-			Int:   big.NewInt(res),
-			Exp:   0,
-			Valid: true,
+		num, err = parseDecimal(res)
+	case *string:
+		if res == nil {
+			return pgtype.Numeric{}, nil
 		}
+		return SetNumericFieldE(*res, opts...)
 	case float64:
-		f := res
-		bigF := new(big.Float).SetFloat64(f)
-		var num pgtype.Numeric
-		num.Int, _ = bigF.Int(nil)
-		num.Exp = int32(bigF.MantExp(nil)) - int32(len(bigF.Text('f', -1)))
-		num.Valid = true
-		return num
+		num = floatToNumeric(res, 64)
 	case float32:
-		f := res
-		bigF := new(big.Float).SetFloat64(float64(f))
-		var num pgtype.Numeric
-		num.Int, _ = bigF.Int(nil)
-		num.Exp = int32(bigF.MantExp(nil)) - int32(len(bigF.Text('f', -1)))
-		num.Valid = true
-		return num
-
+		num = floatToNumeric(float64(res), 32)
+	case *float64:
+		if res == nil {
+			return pgtype.Numeric{}, nil
+		}
+		num = floatToNumeric(*res, 64)
+	case *float32:
+		if res == nil {
+			return pgtype.Numeric{}, nil
+		}
+		num = floatToNumeric(float64(*res), 32)
+	case uint64:
+		num = pgtype.Numeric{Int: new(big.Int).SetUint64(res), Valid: true}
+	case *big.Int:
+		if res == nil {
+			return pgtype.Numeric{}, nil
+		}
+		num = pgtype.Numeric{Int: new(big.Int).Set(res), Valid: true}
+	case *big.Rat:
+		if res == nil {
+			return pgtype.Numeric{}, nil
+		}
+		num, err = ratToNumeric(res, cfg)
+	case *big.Float:
+		if res == nil {
+			return pgtype.Numeric{}, nil
+		}
+		if res.IsInf() {
+			num = floatToNumeric(math.Inf(res.Sign()), 64)
+		} else {
+			num, err = parseDecimal(res.Text('e', -1))
+		}
+	case pgtype.Numeric:
+		if !res.Valid {
+			return pgtype.Numeric{}, nil
+		}
+		num = res
 	default:
-		return pgtype.Numeric{
-			Exp:   0,
-			Valid: false,
+		n, valid, intErr := intFromAny(i)
+		if intErr != nil {
+			return pgtype.Numeric{}, newConvertError[pgtype.Numeric](i, intErr)
+		}
+		if !valid {
+			return pgtype.Numeric{}, nil
 		}
+		num = pgtype.Numeric{Int: big.NewInt(n), Valid: true}
+	}
+	if err != nil {
+		return pgtype.Numeric{}, newConvertError[pgtype.Numeric](i, err)
+	}
 
+	num, err = applyNumericConfig(num, cfg)
+	if err != nil {
+		return pgtype.Numeric{}, newConvertError[pgtype.Numeric](i, err)
 	}
+	return num, nil
 }
 
 // SetBoolField sets a bool or int or int32 or int64 or float64 to a pgtype.Bool
 // It returns a pgtype.Bool with the bool or int or int32 or int64 or float64 value and a boolean indicating if the value is valid
 // If the value is not a bool or int or int32 or int64 or float64, it returns a pgtype.Bool with a false and false
 // It's useful for converting a bool or int or int32 or int64 or float64 to a pgtype.Bool
+// Strings keep their original meaning: every string except "0" is true
 // @param b any - The value to convert to a pgtype.Bool
 // @return pgtype.Bool - The converted pgtype.Bool
 func SetBoolField(b any) pgtype.Bool {
+	if res, ok := b.(string); ok {
+		return pgtype.Bool{
+			Bool:  res != "0",
+			Valid: true,
+		}
+	}
+	out, _ := SetBoolFieldE(b)
+	return out
+}
+
+// SetBoolFieldE is the strict variant of SetBoolField
+// It returns a *ConvertError instead of an invalid pgtype.Bool when the value is not supported or the string is not a boolean
+// Strings are parsed with strconv.ParseBool after trimming spaces: 1, t, T, TRUE, true, True, 0, f, F, FALSE, false or False
+// Unlike SetBoolField, other strings such as "yes" or "2" are an error, a nil value or null string is not, it returns a pgtype.Bool with false
+// @param b any - The value to convert to a pgtype.Bool
+// @return pgtype.Bool - The converted pgtype.Bool
+// @return error - The conversion error, if any
+func SetBoolFieldE(b any) (pgtype.Bool, error) {
 	switch res := b.(type) {
+	case nil:
+		return pgtype.Bool{}, nil
 	case bool:
 		return pgtype.Bool{
 			Bool:  res,
 			Valid: true,
-		}
+		}, nil
 	case int:
 		return pgtype.Bool{
 			Bool:  res != 0,
 			Valid: true,
-		}
+		}, nil
 	case int32:
 		return pgtype.Bool{
 			Bool:  res != 0,
 			Valid: true,
-		}
+		}, nil
 	case int64:
 		return pgtype.Bool{
 			Bool:  res != 0,
 			Valid: true,
-		}
+		}, nil
 	case float64:
 		return pgtype.Bool{
 			Bool:  res != 0,
 			Valid: true,
-		}
+		}, nil
 	case float32:
 		return pgtype.Bool{
 			Bool:  res != 0,
 			Valid: true,
-		}
+		}, nil
 	case string:
+		if !funcvx.NotNull(res) {
+			return pgtype.Bool{}, nil
+		}
+		v, err := strconv.ParseBool(strings.TrimSpace(res))
+		if err != nil {
+			return pgtype.Bool{}, newConvertError[pgtype.Bool](res, fmt.Errorf("%w: not a boolean", ErrInvalidFormat))
+		}
 		return pgtype.Bool{
-			Bool:  res != "0",
+			Bool:  v,
 			Valid: true,
-		}
+		}, nil
 	default:
-		return pgtype.Bool{
-			Bool:  false,
-			Valid: false,
-		}
+		return pgtype.Bool{}, newConvertError[pgtype.Bool](b, ErrUnsupportedType)
 	}
 }
@@ -0,0 +1,64 @@
+package pgxhelpers
+
+import (
+	"fmt"
+
+	"github.com/ChungNQ511/vnw-helpers/funcvx"
+	"github.com/ChungNQ511/vnw-helpers/strconvx"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// SetHstoreField sets a map or an hstore text to a pgtype.Hstore
+// It accepts map[string]string, map[string]*string (a nil value is an hstore NULL value), pgtype.Hstore and hstore text
+// nil maps and null strings are NULL, empty maps are an empty hstore
+// If the value is not supported or the text is not a valid hstore, it returns nil (NULL)
+// It's useful for converting attribute maps to an hstore column
+// @param v any - The value to convert to a pgtype.Hstore
+// @return pgtype.Hstore - The converted pgtype.Hstore, nil for NULL
+func SetHstoreField(v any) pgtype.Hstore {
+	out, _ := SetHstoreFieldE(v)
+	return out
+}
+
+// SetHstoreFieldE is the strict variant of SetHstoreField
+// It returns a *ConvertError when the value is not supported or the text is not a valid hstore
+// @param v any - The value to convert to a pgtype.Hstore
+// @return pgtype.Hstore - The converted pgtype.Hstore, nil for NULL
+// @return error - The conversion error, if any
+func SetHstoreFieldE(v any) (pgtype.Hstore, error) {
+	switch val := v.(type) {
+	case nil:
+		return nil, nil
+	case pgtype.Hstore:
+		return val, nil
+	case map[string]*string:
+		if val == nil {
+			return nil, nil
+		}
+		return pgtype.Hstore(val), nil
+	case map[string]string:
+		if val == nil {
+			return nil, nil
+		}
+		out := make(pgtype.Hstore, len(val))
+		for k, s := range val {
+			out[k] = &s
+		}
+		return out, nil
+	case string:
+		if !funcvx.NotNull(val) {
+			return nil, nil
+		}
+		pairs, err := strconvx.ParseHstore(val)
+		if err != nil {
+			return nil, newConvertError[pgtype.Hstore](v, fmt.Errorf("%w: %v", ErrInvalidFormat, err))
+		}
+		return pgtype.Hstore(pairs), nil
+	case *string:
+		if val == nil {
+			return nil, nil
+		}
+		return SetHstoreFieldE(*val)
+	}
+	return nil, newConvertError[pgtype.Hstore](v, ErrUnsupportedType)
+}
@@ -0,0 +1,464 @@
+package pgxhelpers
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ChungNQ511/vnw-helpers/funcvx"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ErrAmbiguousInterval is returned when an interval with months is converted to a time.Duration
+// A month has no fixed length, use RevertPgIntervalParts and IntervalParts.AddTo instead
+var ErrAmbiguousInterval = errors.New("interval with months has no fixed duration")
+
+const (
+	microsPerSecond = int64(time.Second / time.Microsecond)
+	microsPerDay    = 24 * int64(time.Hour/time.Microsecond)
+	daysPerMonth    = 30 // PostgreSQL's convention when cascading fractional months
+)
+
+// IntervalParts is the months, days and microseconds of a PostgreSQL interval, kept separate like PostgreSQL does
+// @field Months int32 - The number of months, a year is 12 months
+// @field Days int32 - The number of days, a week is 7 days
+// @field Microseconds int64 - The time part in microseconds
+type IntervalParts struct {
+	Months       int32
+	Days         int32
+	Microseconds int64
+}
+
+// AddTo adds the interval to a time.Time the way PostgreSQL adds an interval to a timestamp
+// Months and days are calendar-aware (e.g. 1 month after 31 January), the time part is added last
+// @param t time.Time - The time to add the interval to
+// @return time.Time - The result
+func (p IntervalParts) AddTo(t time.Time) time.Time {
+	return t.AddDate(0, int(p.Months), int(p.Days)).Add(time.Duration(p.Microseconds) * time.Microsecond)
+}
+
+// String formats the interval like PostgreSQL's default output, e.g. "1 year 2 mons 3 days 04:05:06"
+// @return string - The formatted interval
+func (p IntervalParts) String() string {
+	var parts []string
+	plural := func(n int64, unit string) string {
+		if n == 1 || n == -1 {
+			return fmt.Sprintf("%d %s", n, unit)
+		}
+		return fmt.Sprintf("%d %ss", n, unit)
+	}
+	if years := p.Months / 12; years != 0 {
+		parts = append(parts, plural(int64(years), "year"))
+	}
+	if months := p.Months % 12; months != 0 {
+		parts = append(parts, plural(int64(months), "mon"))
+	}
+	if p.Days != 0 {
+		parts = append(parts, plural(int64(p.Days), "day"))
+	}
+	if p.Microseconds != 0 || len(parts) == 0 {
+		micros, sign := p.Microseconds, ""
+		if micros < 0 {
+			micros, sign = -micros, "-"
+		} else if len(parts) > 0 && (p.Months < 0 || p.Days < 0) {
+			sign = "+"
+		}
+		clock := fmt.Sprintf("%s%02d:%02d:%02d", sign, micros/(3600*microsPerSecond), micros/(60*microsPerSecond)%60, micros/microsPerSecond%60)
+		if frac := micros % microsPerSecond; frac != 0 {
+			clock += strings.TrimRight(fmt.Sprintf(".%06d", frac), "0")
+		}
+		parts = append(parts, clock)
+	}
+	return strings.Join(parts, " ")
+}
+
+// SetIntervalField sets a time.Duration, an ISO 8601 duration or a PostgreSQL interval string to a pgtype.Interval
+// Strings can be ISO 8601 ("P1Y2M3DT4H5M6S", "P2W", "-P1D") or PostgreSQL interval text
+// ("1 day 02:00:00", "1 year 2 mons", "-1 days +02:00:00", "@ 3 hours ago")
+// Months, days and microseconds are kept separate, a time.Duration only sets microseconds
+// If the value is not supported or cannot be parsed, it returns a pgtype.Interval with zeros and false
+// It's useful for converting SLA windows and subscription periods to a pgtype.Interval
+// @param v any - The value to convert to a pgtype.Interval
+// @return pgtype.Interval - The converted pgtype.Interval
+func SetIntervalField(v any) pgtype.Interval {
+	out, _ := SetIntervalFieldE(v)
+	return out
+}
+
+// SetIntervalFieldE is the strict variant of SetIntervalField
+// It returns a *ConvertError instead of an invalid pgtype.Interval when the value is not supported or cannot be parsed
+// A nil value, nil pointer or null string is not an error, it returns a pgtype.Interval with false
+// @param v any - The value to convert to a pgtype.Interval
+// @return pgtype.Interval - The converted pgtype.Interval
+// @return error - The conversion error, if any
+func SetIntervalFieldE(v any) (pgtype.Interval, error) {
+	switch val := v.(type) {
+	case nil:
+	case time.Duration:
+		return pgtype.Interval{Microseconds: val.Microseconds(), Valid: true}, nil
+	case *time.Duration:
+		if val != nil {
+			return pgtype.Interval{Microseconds: val.Microseconds(), Valid: true}, nil
+		}
+	case IntervalParts:
+		return pgtype.Interval{Months: val.Months, Days: val.Days, Microseconds: val.Microseconds, Valid: true}, nil
+	case *IntervalParts:
+		if val != nil {
+			return SetIntervalFieldE(*val)
+		}
+	case pgtype.Interval:
+		return val, nil
+	case string:
+		return stringToPgInterval(val)
+	case *string:
+		if val != nil {
+			return stringToPgInterval(*val)
+		}
+	default:
+		return pgtype.Interval{}, newConvertError[pgtype.Interval](v, ErrUnsupportedType)
+	}
+	return pgtype.Interval{}, nil
+}
+
+// intervalAccumulator sums interval components, cascading fractions down like PostgreSQL
+// (0.5 month = 15 days, 0.5 day = 12 hours)
+// Whole units are summed in int64 so large microsecond counts keep their precision
+type intervalAccumulator struct {
+	months, days int64
+	micros       int64
+}
+
+// add parses a number and adds that many units of the given size to the accumulator
+// @param number string - The number of units, e.g. "3" or "-1.5"
+// @param sign int64 - 1, or -1 to negate the number
+// @param unit string - "month", "day" or "micro"
+// @param size int64 - The number of months, days or microseconds per unit
+// @return error - ErrInvalidFormat if the number is invalid, ErrOverflow if a component does not fit in an int64
+func (a *intervalAccumulator) add(number string, sign int64, unit string, size int64) error {
+	whole, frac, err := splitIntervalNumber(number)
+	if err != nil {
+		return err
+	}
+	whole, ok := mulInt64(whole, sign)
+	if !ok {
+		return ErrOverflow
+	}
+	return a.addParts(whole, frac*float64(sign), unit, size)
+}
+
+// addParts adds whole plus frac units of the given size to the accumulator, the fraction cascades to smaller components
+// @param whole int64 - The whole number of units
+// @param frac float64 - The fraction of a unit, with the same sign as whole
+// @param unit string - "month", "day" or "micro"
+// @param size int64 - The number of months, days or microseconds per unit
+// @return error - ErrOverflow if a component does not fit in an int64
+func (a *intervalAccumulator) addParts(whole int64, frac float64, unit string, size int64) error {
+	n, ok := mulInt64(whole, size)
+	sum := func(dst *int64, v int64) {
+		var fits bool
+		*dst, fits = addInt64(*dst, v)
+		ok = ok && fits
+	}
+
+	// the whole units go to their own component, the fraction cascades down
+	fracMicros := frac * float64(size)
+	switch unit {
+	case "month":
+		fm := fracMicros
+		fd := (fm - math.Trunc(fm)) * daysPerMonth
+		sum(&a.months, n)
+		sum(&a.months, int64(fm))
+		sum(&a.days, int64(fd))
+		fracMicros = (fd - math.Trunc(fd)) * float64(microsPerDay)
+	case "day":
+		fd := fracMicros
+		sum(&a.days, n)
+		sum(&a.days, int64(fd))
+		fracMicros = (fd - math.Trunc(fd)) * float64(microsPerDay)
+	default:
+		sum(&a.micros, n)
+	}
+	sum(&a.micros, int64(math.Round(fracMicros)))
+
+	if !ok {
+		return ErrOverflow
+	}
+	return nil
+}
+
+// interval converts the accumulator to a pgtype.Interval
+// @return pgtype.Interval - The interval
+// @return error - ErrOverflow if the months or days do not fit in an int32
+func (a *intervalAccumulator) interval() (pgtype.Interval, error) {
+	if a.months > math.MaxInt32 || a.months < math.MinInt32 || a.days > math.MaxInt32 || a.days < math.MinInt32 {
+		return pgtype.Interval{}, ErrOverflow
+	}
+	return pgtype.Interval{Months: int32(a.months), Days: int32(a.days), Microseconds: a.micros, Valid: true}, nil
+}
+
+// splitIntervalNumber splits a number into its whole part and its fraction, the whole part is parsed exactly
+// @param s string - The number, e.g. "12", "-1.5" or "2e3"
+// @return int64 - The whole part
+// @return float64 - The fraction, with the same sign as the number
+// @return error - ErrInvalidFormat if s is not a number, ErrOverflow if the whole part does not fit in an int64
+func splitIntervalNumber(s string) (int64, float64, error) {
+	if whole, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return whole, 0, nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if (err != nil && !errors.Is(err, strconv.ErrRange)) || math.IsNaN(f) {
+		return 0, 0, fmt.Errorf("%w: invalid number %q", ErrInvalidFormat, s)
+	}
+
+	intPart, fracPart, hasDot := strings.Cut(s, ".")
+	if !hasDot || strings.ContainsAny(s, "eEpPxX") {
+		if f >= math.MaxInt64 || f < math.MinInt64 {
+			return 0, 0, ErrOverflow
+		}
+		whole := math.Trunc(f)
+		return int64(whole), f - whole, nil
+	}
+
+	var whole int64
+	if digits := strings.TrimLeft(intPart, "+-"); digits != "" {
+		if whole, err = strconv.ParseInt(digits, 10, 64); err != nil {
+			return 0, 0, ErrOverflow
+		}
+	}
+	frac, _ := strconv.ParseFloat("0."+fracPart, 64)
+	if strings.HasPrefix(intPart, "-") {
+		whole, frac = -whole, -frac
+	}
+	return whole, frac, nil
+}
+
+// addInt64 adds two int64 values
+// @param a int64 - The first value
+// @param b int64 - The second value
+// @return int64 - The sum
+// @return bool - False if the sum overflows
+func addInt64(a, b int64) (int64, bool) {
+	sum := a + b
+	return sum, (sum > a) == (b > 0)
+}
+
+// mulInt64 multiplies two int64 values
+// @param a int64 - The first value
+// @param b int64 - The second value
+// @return int64 - The product
+// @return bool - False if the product overflows
+func mulInt64(a, b int64) (int64, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+	product := a * b
+	return product, product/b == a && !(a == -1 && b == math.MinInt64) && !(b == -1 && a == math.MinInt64)
+}
+
+// intervalUnits maps PostgreSQL interval unit names to their component and size
+var intervalUnits = map[string]struct {
+	unit string
+	size int64
+}{
+	"millennium": {"month", 12000}, "millenniums": {"month", 12000}, "millennia": {"month", 12000},
+	"century": {"month", 1200}, "centuries": {"month", 1200},
+	"decade": {"month", 120}, "decades": {"month", 120},
+	"y": {"month", 12}, "yr": {"month", 12}, "yrs": {"month", 12}, "year": {"month", 12}, "years": {"month", 12},
+	"mon": {"month", 1}, "mons": {"month", 1}, "month": {"month", 1}, "months": {"month", 1},
+	"w": {"day", 7}, "week": {"day", 7}, "weeks": {"day", 7},
+	"d": {"day", 1}, "day": {"day", 1}, "days": {"day", 1},
+	"h": {"micro", 3600e6}, "hr": {"micro", 3600e6}, "hrs": {"micro", 3600e6}, "hour": {"micro", 3600e6}, "hours": {"micro", 3600e6},
+	"m": {"micro", 60e6}, "min": {"micro", 60e6}, "mins": {"micro", 60e6}, "minute": {"micro", 60e6}, "minutes": {"micro", 60e6},
+	"s": {"micro", 1e6}, "sec": {"micro", 1e6}, "secs": {"micro", 1e6}, "second": {"micro", 1e6}, "seconds": {"micro", 1e6},
+	"ms": {"micro", 1e3}, "msec": {"micro", 1e3}, "msecs": {"micro", 1e3}, "millisecond": {"micro", 1e3}, "milliseconds": {"micro", 1e3},
+	"us": {"micro", 1}, "usec": {"micro", 1}, "usecs": {"micro", 1}, "microsecond": {"micro", 1}, "microseconds": {"micro", 1},
+}
+
+// stringToPgInterval converts an ISO 8601 duration or a PostgreSQL interval string to a pgtype.Interval
+// @param s string - The value to convert to a pgtype.Interval
+// @return pgtype.Interval - The converted pgtype.Interval
+// @return error - A *ConvertError if the string cannot be parsed
+func stringToPgInterval(s string) (pgtype.Interval, error) {
+	if !funcvx.NotNull(s) {
+		return pgtype.Interval{}, nil
+	}
+
+	input := strings.ToLower(strings.TrimSpace(s))
+	var (
+		out pgtype.Interval
+		err error
+	)
+	if strings.HasPrefix(strings.TrimLeft(input, "+-"), "p") {
+		out, err = parseISODuration(input)
+	} else {
+		out, err = parsePgIntervalText(input)
+	}
+	if err != nil {
+		return pgtype.Interval{}, newConvertError[pgtype.Interval](s, err)
+	}
+	return out, nil
+}
+
+// parseISODuration parses an ISO 8601 duration such as "p1y2m3dt4h5m6.5s", the input must be lower case
+// @param s string - The duration
+// @return pgtype.Interval - The parsed pgtype.Interval
+// @return error - ErrInvalidFormat or ErrOverflow
+func parseISODuration(s string) (pgtype.Interval, error) {
+	sign := int64(1)
+	switch s[0] {
+	case '-':
+		sign, s = -1, s[1:]
+	case '+':
+		s = s[1:]
+	}
+	s = s[1:] // p
+	if s == "" || s == "t" {
+		return pgtype.Interval{}, fmt.Errorf("%w: empty ISO 8601 duration", ErrInvalidFormat)
+	}
+
+	var acc intervalAccumulator
+	inTime, timeFields := false, 0
+	for s != "" {
+		if s[0] == 't' {
+			if inTime {
+				return pgtype.Interval{}, fmt.Errorf("%w: repeated T designator", ErrInvalidFormat)
+			}
+			inTime, s = true, s[1:]
+			continue
+		}
+
+		i := strings.IndexFunc(s, func(r rune) bool { return r >= 'a' && r <= 'z' })
+		if i <= 0 {
+			return pgtype.Interval{}, fmt.Errorf("%w: expected a number followed by a designator", ErrInvalidFormat)
+		}
+		number := strings.ReplaceAll(s[:i], ",", ".")
+
+		var err error
+		switch designator := s[i]; {
+		case !inTime && designator == 'y':
+			err = acc.add(number, sign, "month", 12)
+		case !inTime && designator == 'm':
+			err = acc.add(number, sign, "month", 1)
+		case !inTime && designator == 'w':
+			err = acc.add(number, sign, "day", 7)
+		case !inTime && designator == 'd':
+			err = acc.add(number, sign, "day", 1)
+		case inTime && designator == 'h':
+			err = acc.add(number, sign, "micro", 3600e6)
+		case inTime && designator == 'm':
+			err = acc.add(number, sign, "micro", 60e6)
+		case inTime && designator == 's':
+			err = acc.add(number, sign, "micro", 1e6)
+		default:
+			return pgtype.Interval{}, fmt.Errorf("%w: unexpected designator %q", ErrInvalidFormat, designator)
+		}
+		if err != nil {
+			return pgtype.Interval{}, err
+		}
+		if inTime {
+			timeFields++
+		}
+		s = s[i+1:]
+	}
+	if inTime && timeFields == 0 {
+		return pgtype.Interval{}, fmt.Errorf("%w: empty time part after the T designator", ErrInvalidFormat)
+	}
+	return acc.interval()
+}
+
+// parsePgIntervalText parses PostgreSQL interval text such as "1 year 2 mons 3 days 04:05:06", the input must be lower case
+// @param s string - The interval text
+// @return pgtype.Interval - The parsed pgtype.Interval
+// @return error - ErrInvalidFormat or ErrOverflow
+func parsePgIntervalText(s string) (pgtype.Interval, error) {
+	fields := strings.Fields(strings.TrimPrefix(s, "@"))
+	if len(fields) == 0 {
+		return pgtype.Interval{}, fmt.Errorf("%w: empty interval", ErrInvalidFormat)
+	}
+
+	sign := int64(1)
+	if fields[len(fields)-1] == "ago" {
+		sign, fields = -1, fields[:len(fields)-1]
+	}
+
+	var acc intervalAccumulator
+	for i := 0; i < len(fields); i++ {
+		field := fields[i]
+
+		if strings.Contains(field, ":") {
+			micros, err := parseClock(field)
+			if err != nil {
+				return pgtype.Interval{}, err
+			}
+			if err := acc.addParts(sign*micros, 0, "micro", 1); err != nil {
+				return pgtype.Interval{}, err
+			}
+			continue
+		}
+
+		// the unit can be attached to the number ("3days") or be the next field ("3 days")
+		numEnd := strings.IndexFunc(field, func(r rune) bool { return r >= 'a' && r <= 'z' })
+		number, unitName := field, ""
+		if numEnd > 0 {
+			number, unitName = field[:numEnd], field[numEnd:]
+		} else if i+1 < len(fields) {
+			i++
+			unitName = fields[i]
+		}
+
+		if unitName == "" {
+			// a bare number is seconds, like PostgreSQL
+			unitName = "seconds"
+		}
+		unit, ok := intervalUnits[unitName]
+		if !ok {
+			return pgtype.Interval{}, fmt.Errorf("%w: unknown unit %q", ErrInvalidFormat, unitName)
+		}
+		if err := acc.add(number, sign, unit.unit, unit.size); err != nil {
+			return pgtype.Interval{}, err
+		}
+	}
+	return acc.interval()
+}
+
+// parseClock parses a signed "hh:mm[:ss[.ffffff]]" time field to microseconds
+// @param s string - The time field
+// @return int64 - The number of microseconds
+// @return error - ErrInvalidFormat if the field is not a clock value, ErrOverflow if the hours do not fit
+func parseClock(s string) (int64, error) {
+	sign := int64(1)
+	switch s[0] {
+	case '-':
+		sign, s = -1, s[1:]
+	case '+':
+		s = s[1:]
+	}
+
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("%w: invalid time %q", ErrInvalidFormat, s)
+	}
+	hours, errH := strconv.ParseInt(parts[0], 10, 64)
+	minutes, errM := strconv.ParseInt(parts[1], 10, 64)
+	if errH != nil || errM != nil || hours < 0 || minutes < 0 || minutes > 59 {
+		return 0, fmt.Errorf("%w: invalid time %q", ErrInvalidFormat, s)
+	}
+	var seconds float64
+	if len(parts) == 3 {
+		var err error
+		seconds, err = strconv.ParseFloat(parts[2], 64)
+		if err != nil || math.IsNaN(seconds) || seconds < 0 || seconds >= 60 {
+			return 0, fmt.Errorf("%w: invalid time %q", ErrInvalidFormat, s)
+		}
+	}
+
+	micros, fitsH := mulInt64(hours, 3600*microsPerSecond)
+	micros, fits := addInt64(micros, minutes*60*microsPerSecond+int64(math.Round(seconds*float64(microsPerSecond))))
+	if !fitsH || !fits {
+		return 0, ErrOverflow
+	}
+	return sign * micros, nil
+}
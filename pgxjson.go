@@ -0,0 +1,183 @@
+package pgxhelpers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// JSONOption configures how SetJSONField treats nil and empty values
+type JSONOption func(*jsonConfig)
+
+// jsonConfig holds the options of SetJSONField
+type jsonConfig struct {
+	nilAsJSONNull bool
+	emptyAsNull   bool
+}
+
+// WithJSONNull stores nil pointers, maps, slices and the JSON literal null as the JSON value 'null' instead of SQL NULL
+// @return JSONOption - The option
+func WithJSONNull() JSONOption {
+	return func(c *jsonConfig) {
+		c.nilAsJSONNull = true
+	}
+}
+
+// WithEmptyJSONAsNull stores empty maps, slices, objects ({}) and arrays ([]) as SQL NULL
+// @return JSONOption - The option
+func WithEmptyJSONAsNull() JSONOption {
+	return func(c *jsonConfig) {
+		c.emptyAsNull = true
+	}
+}
+
+// SetJSONField marshals any Go value to a json.RawMessage for a json or jsonb column
+// A nil result is sent as SQL NULL by pgx, by default nil pointers, maps, slices and the JSON literal null become SQL NULL
+// []byte, json.RawMessage and string inputs are treated as raw JSON and validated instead of being marshaled
+// If the value cannot be marshaled or the raw JSON is invalid, it returns nil (SQL NULL)
+// It's useful for converting a struct or map to a json or jsonb column
+// @param v T - The value to marshal
+// @param opts ...JSONOption - The nil and empty value policy
+// @return json.RawMessage - The marshaled JSON, or nil for SQL NULL
+func SetJSONField[T any](v T, opts ...JSONOption) json.RawMessage {
+	out, _ := SetJSONFieldE(v, opts...)
+	return out
+}
+
+// SetJSONFieldE is the strict variant of SetJSONField
+// It returns a *ConvertError when the value cannot be marshaled or the raw JSON is invalid
+// @param v T - The value to marshal
+// @param opts ...JSONOption - The nil and empty value policy
+// @return json.RawMessage - The marshaled JSON, or nil for SQL NULL
+// @return error - The conversion error, if any
+func SetJSONFieldE[T any](v T, opts ...JSONOption) (json.RawMessage, error) {
+	var cfg jsonConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	var raw []byte
+	switch val := any(v).(type) {
+	case nil:
+		return cfg.null(), nil
+	case json.RawMessage:
+		raw = val
+	case []byte:
+		raw = val
+	case string:
+		raw = []byte(val)
+	case *string:
+		if val == nil {
+			return cfg.null(), nil
+		}
+		raw = []byte(*val)
+	default:
+		rv := reflect.ValueOf(val)
+		switch rv.Kind() {
+		case reflect.Pointer, reflect.Interface:
+			if rv.IsNil() {
+				return cfg.null(), nil
+			}
+		case reflect.Map, reflect.Slice:
+			if rv.IsNil() {
+				return cfg.null(), nil
+			}
+			if rv.Len() == 0 && cfg.emptyAsNull {
+				return nil, nil
+			}
+		}
+
+		b, err := json.Marshal(val)
+		if err != nil {
+			return nil, &ConvertError{Input: v, Target: "json", Err: err}
+		}
+		return cfg.normalize(b), nil
+	}
+
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	if !json.Valid(raw) {
+		return nil, &ConvertError{Input: v, Target: "json", Err: fmt.Errorf("%w: not valid JSON", ErrInvalidFormat)}
+	}
+	return cfg.normalize(bytes.Clone(raw)), nil
+}
+
+// null returns the value stored for nil inputs, SQL NULL or the JSON literal null
+// @return json.RawMessage - nil or "null"
+func (c jsonConfig) null() json.RawMessage {
+	if c.nilAsJSONNull {
+		return json.RawMessage("null")
+	}
+	return nil
+}
+
+// normalize applies the nil and empty value policy to marshaled or raw JSON
+// @param b []byte - The valid JSON
+// @return json.RawMessage - The JSON, or nil for SQL NULL
+func (c jsonConfig) normalize(b []byte) json.RawMessage {
+	switch string(b) {
+	case "null":
+		return c.null()
+	case "{}", "[]":
+		if c.emptyAsNull {
+			return nil
+		}
+	}
+	return b
+}
+
+// RevertJSONField unmarshals a json or jsonb value into T
+// It accepts []byte, json.RawMessage, string, pgtype.Text, pointers to them, a driver.Valuer such as sql.NullString
+// and the decoded value pgx returns when scanning into any
+// NULL and the JSON literal null return the zero value of T
+// It's useful for converting a json or jsonb column to a typed struct
+// @param v any - The value to unmarshal
+// @return T - The unmarshaled value
+// @return error - A *ConvertError if the value is not valid JSON for T
+func RevertJSONField[T any](v any) (T, error) {
+	var out T
+
+	src, _, err := revertSource(v)
+	if err != nil {
+		return out, newConvertError[T](v, err)
+	}
+
+	var raw []byte
+	switch val := src.(type) {
+	case nil:
+		return out, nil
+	case json.RawMessage:
+		raw = val
+	case []byte:
+		raw = val
+	case string:
+		raw = []byte(val)
+	case pgtype.Text:
+		s, _, err := textFromAny(val)
+		if err != nil {
+			return out, newConvertError[T](v, err)
+		}
+		raw = []byte(s)
+	default:
+		// pgx decodes json into map[string]any, []any, ... when scanning into any
+		b, err := json.Marshal(val)
+		if err != nil {
+			return out, newConvertError[T](v, err)
+		}
+		raw = b
+	}
+
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || string(raw) == "null" {
+		return out, nil
+	}
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return out, newConvertError[T](v, err)
+	}
+	return out, nil
+}
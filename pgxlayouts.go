@@ -0,0 +1,230 @@
+package pgxhelpers
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ChungNQ511/vnw-helpers/datecvx"
+)
+
+// LayoutRegistry is an ordered list of time layouts tried by SetDateField, SetTimestampField and SetTimestamptzField
+// The first layout that parses the whole string wins, so more specific layouts should come first
+// A registry is safe for concurrent use, layouts can be registered while other goroutines are parsing
+type LayoutRegistry struct {
+	mu      sync.Mutex
+	layouts atomic.Pointer[[]string]
+}
+
+// NewLayoutRegistry creates a registry with the given layouts, in order
+// Start from DefaultLayouts to keep the built-in formats
+// @param layouts ...string - The time layouts, e.g. "2006-01-02"
+// @return *LayoutRegistry - The new registry
+func NewLayoutRegistry(layouts ...string) *LayoutRegistry {
+	r := &LayoutRegistry{}
+	r.store(dedupLayouts(layouts))
+	return r
+}
+
+// Layouts returns a copy of the layouts, in the order they are tried
+// @return []string - The layouts
+func (r *LayoutRegistry) Layouts() []string {
+	return slices.Clone(r.load())
+}
+
+// Register adds layouts after the existing ones, layouts that are already registered keep their place
+// @param layouts ...string - The layouts to add
+func (r *LayoutRegistry) Register(layouts ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.store(dedupLayouts(append(r.Layouts(), layouts...)))
+}
+
+// RegisterFirst adds layouts before the existing ones, layouts that are already registered are moved to the front
+// @param layouts ...string - The layouts to add
+func (r *LayoutRegistry) RegisterFirst(layouts ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.store(dedupLayouts(append(slices.Clone(layouts), r.load()...)))
+}
+
+// Remove removes layouts from the registry
+// @param layouts ...string - The layouts to remove
+func (r *LayoutRegistry) Remove(layouts ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.store(slices.DeleteFunc(r.Layouts(), func(l string) bool {
+		return slices.Contains(layouts, l)
+	}))
+}
+
+// load returns the current layouts without copying them
+// Callers must not modify the slice, it is shared with concurrent readers
+// @return []string - The layouts, nil for a zero registry
+func (r *LayoutRegistry) load() []string {
+	if p := r.layouts.Load(); p != nil {
+		return *p
+	}
+	return nil
+}
+
+// store replaces the layouts, callers that read before storing must hold the mutex
+// @param layouts []string - The new layouts, the registry keeps the slice
+func (r *LayoutRegistry) store(layouts []string) {
+	r.layouts.Store(&layouts)
+}
+
+// dedupLayouts removes empty and repeated layouts, keeping the first occurrence
+// @param layouts []string - The layouts, in order
+// @return []string - A new slice with the remaining layouts, in order
+func dedupLayouts(layouts []string) []string {
+	out := make([]string, 0, len(layouts))
+	for _, l := range layouts {
+		if l != "" && !slices.Contains(out, l) {
+			out = append(out, l)
+		}
+	}
+	return out
+}
+
+// FormatLayouts converts datecvx.TimeFormat values to layouts for a LayoutRegistry
+// @param formats ...datecvx.TimeFormat - The formats, e.g. datecvx.Date_DDMMYYYY_HHMM
+// @return []string - The layouts
+func FormatLayouts(formats ...datecvx.TimeFormat) []string {
+	out := make([]string, len(formats))
+	for i, f := range formats {
+		out[i] = string(f)
+	}
+	return out
+}
+
+// DefaultLayouts returns the built-in layouts for a date order
+// ISO dates and times come first, then numeric dates like "3/4/2024", "03-04-24 10:00:00" read in the given order,
+// then the other order for zero-padded dates that cannot be read in the first one, e.g. "12/25/2024" with DMY
+// Fractional seconds are accepted after any seconds field
+// @param order DateOrder - The order of numeric dates
+// @return []string - The layouts, in the order they are tried
+func DefaultLayouts(order DateOrder) []string {
+	layouts := []string{
+		"2006-01-02",          // ISO date
+		"2006-01-02 15:04:05", // ISO datetime
+		"2006-01-02 15:04",    // ISO datetime without seconds
+		"2006-01-02T15:04:05", // ISO datetime without zone
+		time.RFC3339,          // e.g. "2025-06-17T15:04:05Z"
+	}
+	yearFirst := []string{
+		"2006/1/2",          // yyyy/m/d
+		"2006/1/2 15:04:05", // yyyy/m/d H:M:S
+	}
+
+	// the fallback is the zero-padded date in the other order, e.g. mm/dd/yyyy for day-first dates
+	dayMonth, fallback := "2/1", "01/02/2006"
+	if order == DateOrderMDY {
+		dayMonth, fallback = "1/2", "02/01/2006"
+	}
+	if order == DateOrderYMD {
+		layouts = append(layouts, yearFirst...)
+	}
+	// d/m/yyyy, d-m-yyyy, d/m/yy and d-m-yy, each with optional H:M:S or H:M
+	for _, sep := range []string{"/", "-"} {
+		for _, year := range []string{"2006", "06"} {
+			date := strings.ReplaceAll(dayMonth, "/", sep) + sep + year
+			layouts = append(layouts, date, date+" 15:04:05", date+" 15:04")
+		}
+	}
+	layouts = append(layouts, fallback)
+	if order != DateOrderYMD {
+		layouts = append(layouts, yearFirst...)
+	}
+	return layouts
+}
+
+// builtinLayouts holds a registry of DefaultLayouts for each date order, used when Policy.Layouts is nil
+// The registries are shared by every Converter and are never modified
+var builtinLayouts = map[DateOrder]*LayoutRegistry{
+	DateOrderDMY: NewLayoutRegistry(DefaultLayouts(DateOrderDMY)...),
+	DateOrderMDY: NewLayoutRegistry(DefaultLayouts(DateOrderMDY)...),
+	DateOrderYMD: NewLayoutRegistry(DefaultLayouts(DateOrderYMD)...),
+}
+
+// layouts returns the registry of the policy, or the built-in one for its date order
+// An unknown DateOrder falls back to the day-first layouts
+// @return *LayoutRegistry - The registry to parse with
+func (c *Converter) layouts() *LayoutRegistry {
+	if c.policy.Layouts != nil {
+		return c.policy.Layouts
+	}
+	if r, ok := builtinLayouts[c.policy.DateOrder]; ok {
+		return r
+	}
+	return builtinLayouts[DateOrderDMY]
+}
+
+// ParseTime parses a string with the layouts of the default policy and returns the layout that matched
+// It's useful for checking how an input will be read by SetDateField, SetTimestampField and SetTimestamptzField
+// @param s string - The string to parse
+// @return time.Time - The parsed time, zone-less strings are in the policy location
+// @return string - The layout that matched
+// @return error - A *ConvertError if no layout matches
+func ParseTime(s string) (time.Time, string, error) {
+	return DefaultConverter().ParseTime(s)
+}
+
+// ParseTime is ParseTime with the rules of the Converter
+// @param s string - The string to parse
+// @return time.Time - The parsed time, zone-less strings are in the policy location
+// @return string - The layout that matched
+// @return error - A *ConvertError if no layout matches
+func (c *Converter) ParseTime(s string) (time.Time, string, error) {
+	t, layout, err := c.parseTime(c.policy.trim(s), c.policy.location())
+	if err != nil {
+		return time.Time{}, "", newConvertError[time.Time](s, err)
+	}
+	return t, layout, nil
+}
+
+// parseTime tries the layouts of the Converter in order, two-digit years are expanded with the year pivot
+// @param s string - The string to parse
+// @param loc *time.Location - The location of zone-less strings
+// @return time.Time - The parsed time
+// @return string - The layout that matched
+// @return error - ErrInvalidFormat if no layout matches, ErrAmbiguousDate if the policy rejects ambiguous dates
+func (c *Converter) parseTime(s string, loc *time.Location) (time.Time, string, error) {
+	r, err := c.readTime(s, loc)
+	if err != nil {
+		return time.Time{}, "", err
+	}
+	if r.Ambiguous && c.policy.RejectAmbiguousDates {
+		return time.Time{}, "", fmt.Errorf("%w: %s or %s", ErrAmbiguousDate, r.Time.Format("2 Jan 2006"), r.Alternative.Format("2 Jan 2006"))
+	}
+	return r.Time, r.Layout, nil
+}
+
+// tryLayouts parses a string with the first matching layout, two-digit years are expanded with the year pivot
+// @param layouts []string - The layouts, in order
+// @param s string - The string to parse
+// @param loc *time.Location - The location of zone-less strings
+// @return time.Time - The parsed time
+// @return string - The layout that matched
+// @return bool - False if no layout matches
+func (c *Converter) tryLayouts(layouts []string, s string, loc *time.Location) (time.Time, string, bool) {
+	for _, layout := range layouts {
+		t, err := time.ParseInLocation(layout, s, loc)
+		if err != nil {
+			continue
+		}
+		if strings.Contains(strings.ReplaceAll(layout, "2006", ""), "06") {
+			year := c.policy.fullYear(t.Year() % 100)
+			full := time.Date(year, t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+			if full.Day() != t.Day() { // 29 February in a year that is not a leap year
+				continue
+			}
+			t = full
+		}
+		return t, layout, true
+	}
+	return time.Time{}, "", false
+}
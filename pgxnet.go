@@ -0,0 +1,236 @@
+package pgxhelpers
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/netip"
+	"strings"
+
+	"github.com/ChungNQ511/vnw-helpers/funcvx"
+)
+
+// SetInetField sets a string, netip.Addr, netip.Prefix, net.IP or net.IPNet to a netip.Prefix for an inet column
+// A plain address becomes a /32 or /128 prefix, host bits are kept ("192.168.1.5/24" is a valid inet)
+// IPv4-mapped IPv6 addresses ("::ffff:1.2.3.4") are normalized to IPv4
+// pgx stores an invalid (zero) netip.Prefix as NULL
+// If the value is not supported or not an address, it returns a zero netip.Prefix
+// It's useful for converting client IPs to an inet column
+// @param v any - The value to convert to a netip.Prefix
+// @return netip.Prefix - The converted netip.Prefix
+func SetInetField(v any) netip.Prefix {
+	out, _ := SetInetFieldE(v)
+	return out
+}
+
+// SetInetFieldE is the strict variant of SetInetField
+// It returns a *ConvertError instead of a zero netip.Prefix when the value is not supported or not an address
+// A nil value, nil pointer or null string is not an error, it returns a zero netip.Prefix
+// @param v any - The value to convert to a netip.Prefix
+// @return netip.Prefix - The converted netip.Prefix
+// @return error - The conversion error, if any
+func SetInetFieldE(v any) (netip.Prefix, error) {
+	p, err := prefixFromAny(v)
+	if err != nil {
+		return netip.Prefix{}, &ConvertError{Input: v, Target: "inet", Err: err}
+	}
+	return p, nil
+}
+
+// SetCidrField sets a string, netip.Addr, netip.Prefix, net.IP or net.IPNet to a netip.Prefix for a cidr column
+// A plain address becomes a /32 or /128 network, IPv4-mapped IPv6 addresses are normalized to IPv4
+// If the value is not supported, not an address or has bits set to the right of the mask (e.g. "192.168.1.5/24"),
+// it returns a zero netip.Prefix
+// It's useful for converting allowlist entries to a cidr column
+// @param v any - The value to convert to a netip.Prefix
+// @return netip.Prefix - The converted netip.Prefix
+func SetCidrField(v any) netip.Prefix {
+	out, _ := SetCidrFieldE(v)
+	return out
+}
+
+// SetCidrFieldE is the strict variant of SetCidrField
+// It returns a *ConvertError instead of a zero netip.Prefix when the value is not supported, not an address
+// or has bits set to the right of the mask, like PostgreSQL rejects it
+// @param v any - The value to convert to a netip.Prefix
+// @return netip.Prefix - The converted netip.Prefix
+// @return error - The conversion error, if any
+func SetCidrFieldE(v any) (netip.Prefix, error) {
+	p, err := prefixFromAny(v)
+	if err != nil {
+		return netip.Prefix{}, &ConvertError{Input: v, Target: "cidr", Err: err}
+	}
+	if p.IsValid() && p.Masked() != p {
+		return netip.Prefix{}, &ConvertError{Input: v, Target: "cidr", Err: fmt.Errorf("%w: value has bits set to right of mask, use %s", ErrInvalidFormat, p.Masked())}
+	}
+	return p, nil
+}
+
+// prefixFromAny converts a supported address value to a normalized netip.Prefix
+// @param v any - The value to convert
+// @return netip.Prefix - The converted netip.Prefix, zero for NULL
+// @return error - ErrUnsupportedType or ErrInvalidFormat
+func prefixFromAny(v any) (netip.Prefix, error) {
+	switch val := v.(type) {
+	case nil:
+		return netip.Prefix{}, nil
+	case netip.Prefix:
+		if !val.IsValid() {
+			return netip.Prefix{}, nil
+		}
+		return normalizePrefix(val), nil
+	case *netip.Prefix:
+		if val == nil {
+			return netip.Prefix{}, nil
+		}
+		return prefixFromAny(*val)
+	case netip.Addr:
+		if !val.IsValid() {
+			return netip.Prefix{}, nil
+		}
+		return addrPrefix(val)
+	case *netip.Addr:
+		if val == nil {
+			return netip.Prefix{}, nil
+		}
+		return prefixFromAny(*val)
+	case net.IP:
+		if len(val) == 0 {
+			return netip.Prefix{}, nil
+		}
+		addr, ok := netip.AddrFromSlice(val)
+		if !ok {
+			return netip.Prefix{}, fmt.Errorf("%w: invalid IP length %d", ErrInvalidFormat, len(val))
+		}
+		return prefixFromAny(addr.Unmap())
+	case net.IPNet:
+		return prefixFromAny(&val)
+	case *net.IPNet:
+		if val == nil || len(val.IP) == 0 {
+			return netip.Prefix{}, nil
+		}
+		addr, ok := netip.AddrFromSlice(val.IP)
+		ones, bits := val.Mask.Size()
+		if !ok || bits == 0 {
+			return netip.Prefix{}, fmt.Errorf("%w: invalid IP network", ErrInvalidFormat)
+		}
+		if addr.Is4In6() && bits == 32 {
+			addr = addr.Unmap()
+		}
+		return normalizePrefix(netip.PrefixFrom(addr, ones)), nil
+	case string:
+		return stringToPrefix(val)
+	case *string:
+		if val == nil {
+			return netip.Prefix{}, nil
+		}
+		return stringToPrefix(*val)
+	}
+	return netip.Prefix{}, ErrUnsupportedType
+}
+
+// stringToPrefix parses an address or an address with a prefix length
+// @param s string - The value to parse
+// @return netip.Prefix - The parsed netip.Prefix, zero for a null string
+// @return error - ErrInvalidFormat if the string is not an address
+func stringToPrefix(s string) (netip.Prefix, error) {
+	if !funcvx.NotNull(s) {
+		return netip.Prefix{}, nil
+	}
+	s = strings.TrimSpace(s)
+
+	if strings.Contains(s, "/") {
+		p, err := netip.ParsePrefix(s)
+		if err != nil {
+			return netip.Prefix{}, fmt.Errorf("%w: %v", ErrInvalidFormat, err)
+		}
+		return normalizePrefix(p), nil
+	}
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("%w: %v", ErrInvalidFormat, err)
+	}
+	return addrPrefix(addr)
+}
+
+// addrPrefix converts a valid address to a normalized single-host prefix (/32 or /128)
+// @param addr netip.Addr - The address
+// @return netip.Prefix - The normalized prefix
+// @return error - ErrInvalidFormat for zoned addresses
+func addrPrefix(addr netip.Addr) (netip.Prefix, error) {
+	if addr.Zone() != "" {
+		return netip.Prefix{}, fmt.Errorf("%w: PostgreSQL does not support IPv6 zones", ErrInvalidFormat)
+	}
+	return normalizePrefix(netip.PrefixFrom(addr, addr.BitLen())), nil
+}
+
+// normalizePrefix converts IPv4-mapped IPv6 prefixes to IPv4
+// @param p netip.Prefix - The valid prefix
+// @return netip.Prefix - The normalized prefix
+func normalizePrefix(p netip.Prefix) netip.Prefix {
+	addr := p.Addr()
+	if addr.Is4In6() && p.Bits() >= 96 {
+		return netip.PrefixFrom(addr.Unmap(), p.Bits()-96)
+	}
+	return p
+}
+
+// SetMacaddrField sets a string, net.HardwareAddr, []byte, [6]byte or [8]byte to a net.HardwareAddr for a macaddr or macaddr8 column
+// Strings can use any PostgreSQL input format: "08:00:2b:01:02:03", "08-00-2b-01-02-03", "08002b:010203",
+// "0800.2b01.0203" or "08002b010203", with 6 bytes for macaddr or 8 bytes for macaddr8
+// pgx stores a nil net.HardwareAddr as NULL
+// If the value is not supported or not a MAC address, it returns nil
+// It's useful for converting device MAC addresses to a macaddr column
+// @param v any - The value to convert to a net.HardwareAddr
+// @return net.HardwareAddr - The converted net.HardwareAddr
+func SetMacaddrField(v any) net.HardwareAddr {
+	out, _ := SetMacaddrFieldE(v)
+	return out
+}
+
+// SetMacaddrFieldE is the strict variant of SetMacaddrField
+// It returns a *ConvertError instead of nil when the value is not supported or not a 6 or 8 byte MAC address
+// A nil value, empty slice or null string is not an error, it returns nil
+// @param v any - The value to convert to a net.HardwareAddr
+// @return net.HardwareAddr - The converted net.HardwareAddr
+// @return error - The conversion error, if any
+func SetMacaddrFieldE(v any) (net.HardwareAddr, error) {
+	var raw []byte
+	switch val := v.(type) {
+	case nil:
+		return nil, nil
+	case net.HardwareAddr:
+		raw = val
+	case []byte:
+		raw = val
+	case [6]byte:
+		raw = val[:]
+	case [8]byte:
+		raw = val[:]
+	case string:
+		if !funcvx.NotNull(val) {
+			return nil, nil
+		}
+		digits := strings.NewReplacer(":", "", "-", "", ".", "").Replace(strings.TrimSpace(val))
+		b, err := hex.DecodeString(digits)
+		if err != nil {
+			return nil, &ConvertError{Input: v, Target: "macaddr", Err: fmt.Errorf("%w: not a MAC address", ErrInvalidFormat)}
+		}
+		raw = b
+	case *string:
+		if val == nil {
+			return nil, nil
+		}
+		return SetMacaddrFieldE(*val)
+	default:
+		return nil, &ConvertError{Input: v, Target: "macaddr", Err: ErrUnsupportedType}
+	}
+
+	switch len(raw) {
+	case 0:
+		return nil, nil
+	case 6, 8:
+		return net.HardwareAddr(append([]byte(nil), raw...)), nil
+	}
+	return nil, &ConvertError{Input: v, Target: "macaddr", Err: fmt.Errorf("%w: a MAC address has 6 or 8 bytes, got %d", ErrInvalidFormat, len(raw))}
+}
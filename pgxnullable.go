@@ -0,0 +1,242 @@
+package pgxhelpers
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Nullable holds a value of T that can be NULL
+// It scans from and encodes to the database with pgx and database/sql, and marshals to JSON as the value or null,
+// so the same field works from an HTTP request through to the database
+// @field V T - The value, the zero value when Valid is false
+// @field Valid bool - False if the value is NULL
+type Nullable[T any] struct {
+	V     T
+	Valid bool
+}
+
+// NewNullable returns a valid Nullable holding v
+// @param v T - The value
+// @return Nullable[T] - The valid Nullable
+func NewNullable[T any](v T) Nullable[T] {
+	return Nullable[T]{V: v, Valid: true}
+}
+
+// NullableFromPtr returns a Nullable holding *p, or a NULL Nullable if p is nil
+// @param p *T - The pointer to the value
+// @return Nullable[T] - The Nullable
+func NullableFromPtr[T any](p *T) Nullable[T] {
+	if p == nil {
+		return Nullable[T]{}
+	}
+	return NewNullable(*p)
+}
+
+// Ptr returns a pointer to a copy of the value, or nil if the value is NULL
+// @return *T - The pointer to the value
+func (n Nullable[T]) Ptr() *T {
+	if !n.Valid {
+		return nil
+	}
+	v := n.V
+	return &v
+}
+
+// ValueOr returns the value, or def if the value is NULL
+// @param def T - The default value
+// @return T - The value or the default value
+func (n Nullable[T]) ValueOr(def T) T {
+	if !n.Valid {
+		return def
+	}
+	return n.V
+}
+
+// nullableState returns the value and whether it's valid, it's used by StructArgs to unwrap Nullable fields
+func (n Nullable[T]) nullableState() (any, bool) {
+	return n.V, n.Valid
+}
+
+// Scan implements sql.Scanner, NULL sets Valid to false
+// Strings are parsed with the Set*FieldE converters when T is a number, bool, time.Time or [16]byte
+// @param src any - The database value
+// @return error - A *ConvertError if the value cannot be stored in T
+func (n *Nullable[T]) Scan(src any) error {
+	if src == nil {
+		*n = Nullable[T]{}
+		return nil
+	}
+
+	var v T
+	if err := scanInto(src, reflect.ValueOf(&v).Elem()); err != nil {
+		return err
+	}
+	*n = Nullable[T]{V: v, Valid: true}
+	return nil
+}
+
+// Value implements driver.Valuer, NULL is returned as nil
+// The value is returned as is for pgx to encode, or through its own Value method if T is a driver.Valuer
+// @return driver.Value - The database value
+// @return error - The error of the inner Value method, if any
+func (n Nullable[T]) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	if valuer, ok := any(n.V).(driver.Valuer); ok {
+		return valuer.Value()
+	}
+	return n.V, nil
+}
+
+// MarshalJSON implements json.Marshaler, NULL is marshaled as null
+// @return []byte - The JSON encoding
+// @return error - The marshal error, if any
+func (n Nullable[T]) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.V)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, null sets Valid to false
+// @param data []byte - The JSON encoding
+// @return error - The unmarshal error, if any
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		*n = Nullable[T]{}
+		return nil
+	}
+
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*n = Nullable[T]{V: v, Valid: true}
+	return nil
+}
+
+// NullableToPg converts a Nullable to the pgtype P with SetField
+// NULL is converted to P with Valid=false
+// It's useful for passing Nullable fields to sqlc generated params
+// @param n Nullable[T] - The Nullable to convert
+// @return P - The converted pgtype
+// @return error - The conversion error, if any
+func NullableToPg[P PgType, T any](n Nullable[T]) (P, error) {
+	if !n.Valid {
+		var zero P
+		return zero, nil
+	}
+	return SetField[P](n.V)
+}
+
+// NullableFromPg converts a pgtype value, or a pointer to a pgtype scalar, to a Nullable like Revert
+// Valid=false is converted to a NULL Nullable
+// It's useful for filling Nullable fields from sqlc generated rows
+// @param v any - The pgtype value to convert
+// @return Nullable[T] - The converted Nullable
+// @return error - A *ConvertError if the value cannot be stored in T
+func NullableFromPg[T any](v any) (Nullable[T], error) {
+	if _, valid, ok := scalarValue(v); ok && !valid {
+		return Nullable[T]{}, nil
+	}
+	if valid, ok := nonScalarValid(v); ok && !valid {
+		return Nullable[T]{}, nil
+	}
+
+	var out T
+	if err := revertInto(v, reflect.ValueOf(&out).Elem()); err != nil {
+		return Nullable[T]{}, err
+	}
+	return NewNullable(out), nil
+}
+
+// scanInto stores a database value in dst
+// Values assignable to dst are set directly, pgtype scalars go through revertScalarInto
+// and driver values are wrapped in the matching pgtype first
+// @param src any - The database value
+// @param dst reflect.Value - The settable destination
+// @return error - A *ConvertError if the value cannot be stored in dst
+func scanInto(src any, dst reflect.Value) error {
+	sv := reflect.ValueOf(src)
+	if sv.Type().AssignableTo(dst.Type()) {
+		dst.Set(sv)
+		return nil
+	}
+	if scanner, ok := dst.Addr().Interface().(sql.Scanner); ok {
+		return scanner.Scan(src)
+	}
+	if _, _, ok := scalarValue(src); ok {
+		return revertScalarInto(src, dst)
+	}
+	if dst.Kind() == reflect.Pointer && dst.Type() != bigRatType {
+		elem := reflect.New(dst.Type().Elem())
+		if err := scanInto(src, elem.Elem()); err != nil {
+			return err
+		}
+		dst.Set(elem)
+		return nil
+	}
+
+	var (
+		scalar any
+		err    error
+	)
+	switch val := src.(type) {
+	case int64:
+		scalar = pgtype.Int8{Int64: val, Valid: true}
+	case float64:
+		scalar = pgtype.Float8{Float64: val, Valid: true}
+	case bool:
+		scalar = pgtype.Bool{Bool: val, Valid: true}
+	case time.Time:
+		scalar = pgtype.Timestamptz{Time: val, Valid: true}
+	case []byte:
+		if dst.Kind() == reflect.Slice && dst.Type().Elem().Kind() == reflect.Uint8 {
+			dst.SetBytes(bytes.Clone(val))
+			return nil
+		}
+		return scanInto(string(val), dst)
+	case string:
+		scalar, err = stringToScalar(val, dst)
+	default:
+		err = ErrUnsupportedType
+	}
+	if err != nil {
+		return revertError(src, dst, err)
+	}
+	return revertScalarInto(scalar, dst)
+}
+
+// stringToScalar parses a database string with the Set*FieldE converter matching the destination type
+// @param s string - The database string
+// @param dst reflect.Value - The destination
+// @return any - The pgtype scalar
+// @return error - The conversion error, if any
+func stringToScalar(s string, dst reflect.Value) (any, error) {
+	switch {
+	case dst.Kind() == reflect.String:
+		return pgtype.Text{String: s, Valid: true}, nil
+	case dst.Type() == timeType:
+		return SetTimestamptzFieldE(s)
+	case dst.Type() == bigRatType:
+		return SetNumericFieldE(s)
+	case dst.Kind() == reflect.Bool:
+		return SetBoolFieldE(s)
+	case dst.Kind() == reflect.Array && dst.Type().Elem().Kind() == reflect.Uint8 && dst.Len() == 16:
+		return SetUUIDFieldE(s)
+	}
+	switch dst.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return SetNumericFieldE(s)
+	}
+	return nil, ErrUnsupportedType
+}
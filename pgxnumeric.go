@@ -0,0 +1,343 @@
+package pgxhelpers
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+	bigTen      = big.NewInt(10)
+	maxInt64Exp = int32(19) // 10^19 > math.MaxInt64
+)
+
+// numericToInt64 converts a valid pgtype.Numeric to an int64
+// It returns ErrFractional if the value has a fractional part and ErrOverflow if it does not fit in an int64
+// @param n pgtype.Numeric - The value to convert, it must be valid
+// @return int64 - The converted int64
+// @return error - The conversion error, if any
+func numericToInt64(n pgtype.Numeric) (int64, error) {
+	if n.NaN || n.InfinityModifier != pgtype.Finite {
+		return 0, ErrNonFinite
+	}
+	if n.Int == nil || n.Int.Sign() == 0 {
+		return 0, nil
+	}
+
+	i := new(big.Int).Set(n.Int)
+	switch {
+	case n.Exp > maxInt64Exp:
+		return 0, ErrOverflow
+	case n.Exp > 0:
+		i.Mul(i, pow10(n.Exp))
+	case n.Exp < 0:
+		var rem big.Int
+		i.QuoRem(i, pow10(-n.Exp), &rem)
+		if rem.Sign() != 0 {
+			return 0, ErrFractional
+		}
+	}
+
+	if !i.IsInt64() {
+		return 0, ErrOverflow
+	}
+	return i.Int64(), nil
+}
+
+// NumericOption configures how SetNumericField converts a value to a pgtype.Numeric
+type NumericOption func(*numericConfig)
+
+// numericConfig holds the options of SetNumericField
+type numericConfig struct {
+	precision int32
+	scale     int32
+	hasScale  bool
+	allowNaN  bool
+	allowInf  bool
+}
+
+// WithNumericScale rounds the value to scale digits after the decimal point (half away from zero, like PostgreSQL)
+// It's useful for money and quantities that are stored with a fixed number of decimals
+// @param scale int32 - The number of digits after the decimal point
+// @return NumericOption - The option
+func WithNumericScale(scale int32) NumericOption {
+	return func(c *numericConfig) {
+		c.scale = scale
+		c.hasScale = true
+	}
+}
+
+// WithNumericPrecision rounds the value to scale digits and checks it fits in precision digits, like a numeric(precision, scale) column
+// A value with more than precision-scale digits before the decimal point returns ErrOverflow
+// @param precision int32 - The total number of significant digits
+// @param scale int32 - The number of digits after the decimal point
+// @return NumericOption - The option
+func WithNumericPrecision(precision, scale int32) NumericOption {
+	return func(c *numericConfig) {
+		c.precision = precision
+		c.scale = scale
+		c.hasScale = true
+	}
+}
+
+// AllowNumericNaN accepts NaN inputs and converts them to a NaN pgtype.Numeric instead of returning ErrNonFinite
+// @return NumericOption - The option
+func AllowNumericNaN() NumericOption {
+	return func(c *numericConfig) {
+		c.allowNaN = true
+	}
+}
+
+// AllowNumericInfinity accepts infinite inputs and converts them to an infinite pgtype.Numeric instead of returning ErrNonFinite
+// PostgreSQL supports numeric infinity since version 14
+// @return NumericOption - The option
+func AllowNumericInfinity() NumericOption {
+	return func(c *numericConfig) {
+		c.allowInf = true
+	}
+}
+
+// numericMaxWeight and numericMaxScale are the PostgreSQL limits of numeric,
+// 131072 digits before the decimal point and 16383 after it
+const (
+	numericMaxWeight = 131072
+	numericMaxScale  = 16383
+)
+
+// parseDecimal parses a decimal string such as "123.456", "-1.5e3" or "NaN" to an exact pgtype.Numeric
+// The number of digits after the decimal point is kept, so "1.50" has Exp -2
+// @param s string - The string to parse, it must not be null
+// @return pgtype.Numeric - The parsed pgtype.Numeric
+// @return error - ErrInvalidFormat if the string is not a decimal number, ErrOverflow if it exceeds the limits of numeric
+func parseDecimal(s string) (pgtype.Numeric, error) {
+	s = strings.TrimSpace(s)
+
+	switch strings.ToLower(s) {
+	case "nan":
+		return pgtype.Numeric{NaN: true, Valid: true}, nil
+	case "infinity", "+infinity", "inf", "+inf":
+		return pgtype.Numeric{InfinityModifier: pgtype.Infinity, Valid: true}, nil
+	case "-infinity", "-inf":
+		return pgtype.Numeric{InfinityModifier: pgtype.NegativeInfinity, Valid: true}, nil
+	}
+
+	mantissa, exponent, hasExponent := s, "", false
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		mantissa, exponent, hasExponent = s[:i], s[i+1:], true
+	}
+
+	sign := ""
+	if mantissa != "" && (mantissa[0] == '-' || mantissa[0] == '+') {
+		sign, mantissa = mantissa[:1], mantissa[1:]
+	}
+
+	intPart, fracPart, _ := strings.Cut(mantissa, ".")
+	digits := intPart + fracPart
+	if digits == "" || strings.Trim(digits, "0123456789") != "" {
+		return pgtype.Numeric{}, fmt.Errorf("%w: not a decimal number", ErrInvalidFormat)
+	}
+
+	exp := int64(-len(fracPart))
+	if hasExponent {
+		e, err := strconv.ParseInt(exponent, 10, 32)
+		if errors.Is(err, strconv.ErrRange) {
+			return pgtype.Numeric{}, fmt.Errorf("%w: exponent out of range", ErrOverflow)
+		}
+		if err != nil {
+			return pgtype.Numeric{}, fmt.Errorf("%w: invalid exponent", ErrInvalidFormat)
+		}
+		exp += e
+	}
+	// reject values PostgreSQL cannot store before building huge powers of ten for them
+	significant := int64(len(strings.TrimLeft(digits, "0")))
+	if exp < -numericMaxScale || exp > numericMaxWeight || (significant > 0 && significant+exp > numericMaxWeight) {
+		return pgtype.Numeric{}, fmt.Errorf("%w: exceeds the limits of numeric", ErrOverflow)
+	}
+
+	i, ok := new(big.Int).SetString(sign+digits, 10)
+	if !ok {
+		return pgtype.Numeric{}, fmt.Errorf("%w: not a decimal number", ErrInvalidFormat)
+	}
+	return pgtype.Numeric{Int: i, Exp: int32(exp), Valid: true}, nil
+}
+
+// floatToNumeric converts a float64 to a pgtype.Numeric using the shortest decimal that round-trips
+// So 123.456 becomes exactly 123.456 and not the binary approximation 123.4560000000000030695
+// @param f float64 - The value to convert
+// @param bitSize int - 32 for a float32 value, 64 for a float64 value
+// @return pgtype.Numeric - The converted pgtype.Numeric
+func floatToNumeric(f float64, bitSize int) pgtype.Numeric {
+	switch {
+	case math.IsNaN(f):
+		return pgtype.Numeric{NaN: true, Valid: true}
+	case math.IsInf(f, 1):
+		return pgtype.Numeric{InfinityModifier: pgtype.Infinity, Valid: true}
+	case math.IsInf(f, -1):
+		return pgtype.Numeric{InfinityModifier: pgtype.NegativeInfinity, Valid: true}
+	}
+	// FormatFloat always returns a valid decimal for finite values
+	n, _ := parseDecimal(strconv.FormatFloat(f, 'e', -1, bitSize))
+	return n
+}
+
+// ratToNumeric converts a big.Rat to a pgtype.Numeric
+// A rational with a terminating decimal expansion is converted exactly, any other value (e.g. 1/3) needs a scale
+// @param r *big.Rat - The value to convert
+// @param cfg numericConfig - The options, only the scale is used
+// @return pgtype.Numeric - The converted pgtype.Numeric
+// @return error - ErrInvalidFormat if the value has no exact decimal and no scale is set
+func ratToNumeric(r *big.Rat, cfg numericConfig) (pgtype.Numeric, error) {
+	// the decimal terminates iff the denominator has no prime factors other than 2 and 5
+	denom := new(big.Int).Set(r.Denom())
+	var twos, fives int32
+	two, five := big.NewInt(2), big.NewInt(5)
+	var rem big.Int
+	for {
+		if q, _ := new(big.Int).QuoRem(denom, two, &rem); rem.Sign() == 0 {
+			denom, twos = q, twos+1
+			continue
+		}
+		if q, _ := new(big.Int).QuoRem(denom, five, &rem); rem.Sign() == 0 {
+			denom, fives = q, fives+1
+			continue
+		}
+		break
+	}
+
+	if denom.Cmp(big.NewInt(1)) == 0 {
+		k := max(twos, fives)
+		i := new(big.Int).Mul(r.Num(), pow10(k))
+		i.Quo(i, r.Denom())
+		return pgtype.Numeric{Int: i, Exp: -k, Valid: true}, nil
+	}
+
+	if !cfg.hasScale {
+		return pgtype.Numeric{}, fmt.Errorf("%w: %s has no exact decimal representation, set a scale", ErrInvalidFormat, r.RatString())
+	}
+	i := new(big.Int).Mul(r.Num(), pow10(cfg.scale+1))
+	i.Quo(i, r.Denom())
+	return roundNumeric(pgtype.Numeric{Int: i, Exp: -(cfg.scale + 1), Valid: true}, cfg.scale), nil
+}
+
+// applyNumericConfig checks NaN and infinity against the options, then applies the scale and precision
+// @param n pgtype.Numeric - The valid value to check
+// @param cfg numericConfig - The options
+// @return pgtype.Numeric - The rounded pgtype.Numeric
+// @return error - ErrNonFinite or ErrOverflow
+func applyNumericConfig(n pgtype.Numeric, cfg numericConfig) (pgtype.Numeric, error) {
+	if n.NaN {
+		if !cfg.allowNaN {
+			return pgtype.Numeric{}, ErrNonFinite
+		}
+		return n, nil
+	}
+	if n.InfinityModifier != pgtype.Finite {
+		if !cfg.allowInf {
+			return pgtype.Numeric{}, ErrNonFinite
+		}
+		return n, nil
+	}
+	if n.Int == nil {
+		n.Int = new(big.Int)
+	}
+
+	if cfg.hasScale {
+		n = roundNumeric(n, cfg.scale)
+	}
+	if cfg.precision > 0 && int32(len(new(big.Int).Abs(n.Int).String())) > cfg.precision {
+		return pgtype.Numeric{}, fmt.Errorf("%w: does not fit in numeric(%d,%d)", ErrOverflow, cfg.precision, cfg.scale)
+	}
+	return n, nil
+}
+
+// roundNumeric rounds a finite pgtype.Numeric to scale digits after the decimal point, half away from zero
+// Values with fewer digits are padded with zeros, so the result always has Exp -scale
+// @param n pgtype.Numeric - The value to round
+// @param scale int32 - The number of digits after the decimal point
+// @return pgtype.Numeric - The rounded pgtype.Numeric
+func roundNumeric(n pgtype.Numeric, scale int32) pgtype.Numeric {
+	i := new(big.Int).Set(n.Int)
+	if n.Exp >= -scale {
+		i.Mul(i, pow10(n.Exp+scale))
+		return pgtype.Numeric{Int: i, Exp: -scale, Valid: true}
+	}
+
+	d := pow10(-scale - n.Exp)
+	var rem big.Int
+	i.QuoRem(i, d, &rem)
+	if rem.Abs(&rem).Lsh(&rem, 1).Cmp(d) >= 0 {
+		i.Add(i, big.NewInt(int64(n.Int.Sign())))
+	}
+	return pgtype.Numeric{Int: i, Exp: -scale, Valid: true}
+}
+
+// pow10 returns 10^n as a *big.Int
+// @param n int32 - The exponent, it must not be negative
+// @return *big.Int - 10^n
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(bigTen, big.NewInt(int64(n)), nil)
+}
+
+// numericString formats a valid pgtype.Numeric as an exact decimal string, like PostgreSQL's numeric output
+// NaN and infinity are formatted as "NaN", "Infinity" and "-Infinity"
+// @param n pgtype.Numeric - The value to format, it must be valid
+// @return string - The decimal string
+func numericString(n pgtype.Numeric) string {
+	switch {
+	case n.NaN:
+		return "NaN"
+	case n.InfinityModifier == pgtype.Infinity:
+		return "Infinity"
+	case n.InfinityModifier == pgtype.NegativeInfinity:
+		return "-Infinity"
+	case n.Int == nil:
+		return "0"
+	}
+
+	digits := new(big.Int).Abs(n.Int).String()
+	sign := ""
+	if n.Int.Sign() < 0 {
+		sign = "-"
+	}
+
+	if n.Exp >= 0 {
+		if n.Int.Sign() == 0 {
+			return "0"
+		}
+		return sign + digits + strings.Repeat("0", int(n.Exp))
+	}
+
+	scale := int(-n.Exp)
+	if len(digits) <= scale {
+		digits = strings.Repeat("0", scale-len(digits)+1) + digits
+	}
+	point := len(digits) - scale
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+// numericToRat converts a valid finite pgtype.Numeric to an exact *big.Rat
+// @param n pgtype.Numeric - The value to convert, it must be valid
+// @return *big.Rat - The converted *big.Rat
+// @return error - ErrNonFinite for NaN and infinity
+func numericToRat(n pgtype.Numeric) (*big.Rat, error) {
+	if n.NaN || n.InfinityModifier != pgtype.Finite {
+		return nil, ErrNonFinite
+	}
+	if n.Int == nil {
+		return new(big.Rat), nil
+	}
+
+	r := new(big.Rat).SetInt(n.Int)
+	switch {
+	case n.Exp > 0:
+		r.Mul(r, new(big.Rat).SetInt(pow10(n.Exp)))
+	case n.Exp < 0:
+		r.Quo(r, new(big.Rat).SetInt(pow10(-n.Exp)))
+	}
+	return r, nil
+}
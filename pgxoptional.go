@@ -0,0 +1,157 @@
+package pgxhelpers
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// Optional is a tri-state field for partial updates: absent, explicitly null or set to a value
+// Decoding JSON sets Present only for keys found in the body, so "field not sent" and "field sent as null"
+// stay different, unlike the Set*Field helpers which turn both into Valid=false
+// @field V T - The value, the zero value unless Present and Valid
+// @field Valid bool - False if the field is absent or null
+// @field Present bool - True if the field was sent, even as null
+type Optional[T any] struct {
+	V       T
+	Valid   bool
+	Present bool
+}
+
+// OptionalOf returns a present Optional holding v
+// @param v T - The value
+// @return Optional[T] - The present Optional
+func OptionalOf[T any](v T) Optional[T] {
+	return Optional[T]{V: v, Valid: true, Present: true}
+}
+
+// OptionalNull returns a present Optional that sets the field to NULL
+// @return Optional[T] - The null Optional
+func OptionalNull[T any]() Optional[T] {
+	return Optional[T]{Present: true}
+}
+
+// IsNull reports whether the field was sent as null
+// @return bool - True if the field is present and null
+func (o Optional[T]) IsNull() bool {
+	return o.Present && !o.Valid
+}
+
+// IsZero reports whether the field is absent
+// With Go 1.24 or later, `json:",omitzero"` uses it to leave absent fields out, this module still builds with Go 1.23
+// @return bool - True if the field is absent
+func (o Optional[T]) IsZero() bool {
+	return !o.Present
+}
+
+// Nullable returns the value as a Nullable, absent fields are NULL
+// @return Nullable[T] - The Nullable
+func (o Optional[T]) Nullable() Nullable[T] {
+	return Nullable[T]{V: o.V, Valid: o.Valid}
+}
+
+// ValueOr returns the value, or def if the field is absent or null
+// @param def T - The default value
+// @return T - The value or the default value
+func (o Optional[T]) ValueOr(def T) T {
+	if !o.Valid {
+		return def
+	}
+	return o.V
+}
+
+// MarshalJSON implements json.Marshaler, absent and null fields are marshaled as null
+// @return []byte - The JSON encoding
+// @return error - The marshal error, if any
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	if !o.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(o.V)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, it's only called for keys found in the body so it marks the field present
+// @param data []byte - The JSON encoding
+// @return error - The unmarshal error, if any
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		*o = OptionalNull[T]()
+		return nil
+	}
+
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*o = OptionalOf(v)
+	return nil
+}
+
+// optionalState returns the value and the state of the field, it's used by UpdateColumns and StructArgs
+func (o Optional[T]) optionalState() (any, bool, bool) {
+	return o.V, o.Present, o.Valid
+}
+
+// optionalField is implemented by every Optional[T]
+type optionalField interface {
+	optionalState() (value any, present bool, valid bool)
+}
+
+// UpdateColumns builds the column list and query arguments of an UPDATE from a struct of Optional fields
+// Absent fields are skipped and fields sent as null are written as NULL, fields that are not Optional are ignored
+// A nil *Optional field is absent
+// Columns and tag options follow StructArgs
+// The lists are empty when no field is present
+// It's useful for PATCH endpoints together with UpdateSetClause
+// @param v any - The struct, or a pointer to it
+// @return []string - The column names
+// @return []any - The query arguments, in the same order
+// @return error - An error if v is not a struct or a value cannot be converted
+func UpdateColumns(v any) ([]string, []any, error) {
+	rv, err := structValue(v, "UpdateColumns")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		columns []string
+		args    []any
+	)
+	for _, f := range columnFields(rv.Type()) {
+		fv, ok := columnValue(rv, f)
+		if !ok {
+			continue
+		}
+		if _, ok := fv.Interface().(optionalField); !ok {
+			continue
+		}
+		arg, ok, err := columnArg(f, fv)
+		if err != nil {
+			return nil, nil, err
+		}
+		if ok {
+			columns = append(columns, f.tag.name)
+			args = append(args, arg)
+		}
+	}
+	return columns, args, nil
+}
+
+// UpdateSetClause builds the SET list of an UPDATE, e.g. `"name" = $1, "age" = $2`
+// Placeholders start at start, so the WHERE clause can use the placeholders before or after them
+// @param columns []string - The column names from UpdateColumns
+// @param start int - The number of the first placeholder
+// @return string - The SET list
+func UpdateSetClause(columns []string, start int) string {
+	var builder strings.Builder
+	for i, col := range columns {
+		if i > 0 {
+			builder.WriteString(", ")
+		}
+		builder.WriteString(QuoteIdentifier(col))
+		builder.WriteString(" = $")
+		builder.WriteString(strconv.Itoa(start + i))
+	}
+	return builder.String()
+}
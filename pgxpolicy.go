@@ -0,0 +1,133 @@
+package pgxhelpers
+
+import (
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// DateOrder is the order of the day, month and year in numeric date strings such as "03/04/2024"
+type DateOrder int
+
+const (
+	// DateOrderDMY reads numeric dates as day/month/year, e.g. "03/04/2024" is 3 April 2024
+	DateOrderDMY DateOrder = iota
+	// DateOrderMDY reads numeric dates as month/day/year, e.g. "03/04/2024" is 4 March 2024
+	DateOrderMDY
+	// DateOrderYMD reads numeric dates as year/month/day, e.g. "2024/03/04" is 4 March 2024
+	DateOrderYMD
+)
+
+// String returns the name of the order, e.g. "DMY"
+// @return string - The name of the order
+func (o DateOrder) String() string {
+	switch o {
+	case DateOrderMDY:
+		return "MDY"
+	case DateOrderYMD:
+		return "YMD"
+	default:
+		return "DMY"
+	}
+}
+
+// Policy holds the rules a Converter applies to string and time inputs
+// Start from DefaultPolicy and change the fields you need, the zero Policy does not match the package defaults
+// @field EmptyAsNull bool - Store empty strings as NULL in SetTextField instead of as an empty string
+// @field Trim bool - Trim surrounding whitespace from string inputs before converting them
+// @field Location *time.Location - The location of zone-less strings in SetTimestamptzField, nil means UTC
+// @field DateOrder DateOrder - The order tried first for numeric date strings when Layouts is nil
+// @field YearPivot int - Two-digit years below the pivot are in the 2000s and the rest in the 1900s, 0 puts all of them in the 2000s
+// @field NormalizeUTC bool - Convert SetTimestamptzField results to UTC
+// @field Layouts *LayoutRegistry - The layouts of date and time strings, nil means DefaultLayouts for the DateOrder, a custom registry is used exactly as given
+// @field RejectAmbiguousDates bool - Fail with ErrAmbiguousDate on numeric dates that are valid both day first and month first
+type Policy struct {
+	EmptyAsNull          bool
+	Trim                 bool
+	Location             *time.Location
+	DateOrder            DateOrder
+	YearPivot            int
+	NormalizeUTC         bool
+	Layouts              *LayoutRegistry
+	RejectAmbiguousDates bool
+}
+
+// DefaultPolicy returns the policy used by the package-level functions unless SetDefaultPolicy is called
+// Empty strings are NULL, strings are not trimmed, zone-less times are UTC, dates are day first,
+// two-digit years are in the 2000s and timestamptz values are normalized to UTC
+// @return Policy - The default policy
+func DefaultPolicy() Policy {
+	return Policy{
+		EmptyAsNull:  true,
+		DateOrder:    DateOrderDMY,
+		NormalizeUTC: true,
+	}
+}
+
+// location returns the location of zone-less strings
+// @return *time.Location - The policy location, time.UTC if it is nil
+func (p Policy) location() *time.Location {
+	if p.Location == nil {
+		return time.UTC
+	}
+	return p.Location
+}
+
+// trim trims surrounding whitespace from a string input if the policy asks for it
+// @param s string - The input
+// @return string - The trimmed input, or s unchanged if Trim is false
+func (p Policy) trim(s string) string {
+	if p.Trim {
+		return strings.TrimSpace(s)
+	}
+	return s
+}
+
+// fullYear expands a two-digit year with the year pivot
+// @param yy int - The two-digit year
+// @return int - The full year
+func (p Policy) fullYear(yy int) int {
+	if p.YearPivot > 0 && yy >= p.YearPivot {
+		return 1900 + yy
+	}
+	return 2000 + yy
+}
+
+// Converter converts Go values to pgtypes with a fixed Policy
+// A Converter is immutable and safe for concurrent use, services with different rules can each keep their own
+type Converter struct {
+	policy Policy
+}
+
+// NewConverter creates a Converter with the given policy
+// @param p Policy - The conversion rules
+// @return *Converter - The new Converter
+func NewConverter(p Policy) *Converter {
+	return &Converter{policy: p}
+}
+
+// Policy returns the rules of the Converter
+// @return Policy - The policy
+func (c *Converter) Policy() Policy {
+	return c.policy
+}
+
+// defaultConverter is the Converter behind the package-level functions
+var defaultConverter atomic.Pointer[Converter]
+
+func init() {
+	defaultConverter.Store(NewConverter(DefaultPolicy()))
+}
+
+// DefaultConverter returns the Converter used by the package-level functions
+// @return *Converter - The default Converter
+func DefaultConverter() *Converter {
+	return defaultConverter.Load()
+}
+
+// SetDefaultPolicy replaces the policy used by the package-level functions, it's safe to call concurrently
+// It's meant to be called once at startup, code that needs other rules should use its own Converter
+// @param p Policy - The new default policy
+func SetDefaultPolicy(p Policy) {
+	defaultConverter.Store(NewConverter(p))
+}
@@ -0,0 +1,508 @@
+package pgxhelpers
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/ChungNQ511/vnw-helpers/funcvx"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// RangeElement lists the pgtype structs that can be the bounds of a pgtype.Range
+// int4range, int8range, numrange, daterange, tsrange and tstzrange
+type RangeElement interface {
+	pgtype.Int4 | pgtype.Int8 | pgtype.Numeric | pgtype.Date | pgtype.Timestamp | pgtype.Timestamptz
+}
+
+// RangeBound is one bound of a Range
+// @field Value G - The bound value
+// @field Inclusive bool - True if the bound value is part of the range
+type RangeBound[G any] struct {
+	Value     G
+	Inclusive bool
+}
+
+// Range is a PostgreSQL range with plain Go bounds
+// @field Lower *RangeBound[G] - The lower bound, nil if the range is unbounded below
+// @field Upper *RangeBound[G] - The upper bound, nil if the range is unbounded above
+// @field Empty bool - True for the empty range, which has no bounds
+type Range[G any] struct {
+	Lower *RangeBound[G]
+	Upper *RangeBound[G]
+	Empty bool
+}
+
+// SetRangeField sets two Go bounds to a pgtype.Range, like PostgreSQL's daterange(lower, upper, bounds) constructors
+// Bounds are converted with the matching Set*FieldE function, a nil or null bound is unbounded
+// bounds is "[)", "[]", "(]" or "()", an empty string means "[)"
+// Discrete ranges (int4range, int8range, daterange) are returned in canonical "[)" form, like PostgreSQL does
+// If a bound cannot be converted or lower is greater than upper, it returns a pgtype.Range with false
+// It's useful for converting booking windows and price tiers to a pgtype.Range
+// @param lower any - The lower bound
+// @param upper any - The upper bound
+// @param bounds string - The inclusive/exclusive flags
+// @return pgtype.Range[T] - The converted pgtype.Range
+func SetRangeField[T RangeElement](lower, upper any, bounds string) pgtype.Range[T] {
+	out, _ := SetRangeFieldE[T](lower, upper, bounds)
+	return out
+}
+
+// SetRangeFieldE is the strict variant of SetRangeField
+// It returns a *ConvertError when a bound cannot be converted, the bounds flags are invalid or lower is greater than upper
+// @param lower any - The lower bound
+// @param upper any - The upper bound
+// @param bounds string - The inclusive/exclusive flags
+// @return pgtype.Range[T] - The converted pgtype.Range
+// @return error - The conversion error, if any
+func SetRangeFieldE[T RangeElement](lower, upper any, bounds string) (pgtype.Range[T], error) {
+	if bounds == "" {
+		bounds = "[)"
+	}
+	if len(bounds) != 2 || !strings.Contains("[(", bounds[:1]) || !strings.Contains("])", bounds[1:]) {
+		return pgtype.Range[T]{}, newConvertError[pgtype.Range[T]](bounds, fmt.Errorf("%w: range bounds must be one of [), [], (] or ()", ErrInvalidFormat))
+	}
+
+	out := pgtype.Range[T]{Valid: true}
+	var err error
+	if out.Lower, out.LowerType, err = rangeBound[T](lower, bounds[0] == '['); err != nil {
+		return pgtype.Range[T]{}, newConvertError[pgtype.Range[T]](lower, err)
+	}
+	if out.Upper, out.UpperType, err = rangeBound[T](upper, bounds[1] == ']'); err != nil {
+		return pgtype.Range[T]{}, newConvertError[pgtype.Range[T]](upper, err)
+	}
+
+	if out, err = checkRange(out); err != nil {
+		return pgtype.Range[T]{}, newConvertError[pgtype.Range[T]](fmt.Sprintf("%v%v,%v%v", bounds[:1], lower, upper, bounds[1:]), err)
+	}
+	return out, nil
+}
+
+// ParseRangeField parses a PostgreSQL range literal such as "[2024-01-01,2024-02-01)", "(,100]" or "empty"
+// Bounds can be quoted ("[\"2024-01-01 10:00\",\"2024-01-01 12:00\")") and are converted from strings
+// with the matching Set*FieldE function, discrete ranges are returned in canonical "[)" form
+// It's useful for reading range literals from config files, query strings or tests
+// @param s string - The range literal
+// @return pgtype.Range[T] - The parsed pgtype.Range
+// @return error - A *ConvertError if the literal is invalid
+func ParseRangeField[T RangeElement](s string) (pgtype.Range[T], error) {
+	input := strings.TrimSpace(s)
+	if strings.EqualFold(input, "empty") {
+		return pgtype.Range[T]{LowerType: pgtype.Empty, UpperType: pgtype.Empty, Valid: true}, nil
+	}
+	if len(input) < 3 || !strings.Contains("[(", input[:1]) || !strings.Contains("])", input[len(input)-1:]) {
+		return pgtype.Range[T]{}, newConvertError[pgtype.Range[T]](s, fmt.Errorf("%w: range literal must start with [ or ( and end with ] or )", ErrInvalidFormat))
+	}
+
+	lower, upper, err := splitRangeBounds(input[1 : len(input)-1])
+	if err != nil {
+		return pgtype.Range[T]{}, newConvertError[pgtype.Range[T]](s, err)
+	}
+	bounds := input[:1] + input[len(input)-1:]
+
+	// SetRangeFieldE treats nil as unbounded
+	var lo, up any
+	if lower != nil {
+		lo = *lower
+	}
+	if upper != nil {
+		up = *upper
+	}
+	out, err := SetRangeFieldE[T](lo, up, bounds)
+	if err != nil {
+		var convErr *ConvertError
+		if errors.As(err, &convErr) {
+			err = convErr.Err
+		}
+		return pgtype.Range[T]{}, newConvertError[pgtype.Range[T]](s, err)
+	}
+	return out, nil
+}
+
+// splitRangeBounds splits the inside of a range literal on the comma, handling quotes and backslash escapes
+// Whitespace around a bound is dropped unless it is quoted or escaped, so "[2024-01-01, 2024-02-01)" has two plain dates
+// @param s string - The text between the brackets
+// @return *string - The lower bound, nil if unbounded
+// @return *string - The upper bound, nil if unbounded
+// @return error - ErrInvalidFormat if there is not exactly one separator
+func splitRangeBounds(s string) (*string, *string, error) {
+	var (
+		bounds  []*string
+		current strings.Builder
+		quoted  bool // the current bound had quotes, so "" is an empty string and not unbounded
+		inQuote bool
+		literal int // the length of current up to its last quoted or escaped byte, which is never trimmed
+	)
+	flush := func() {
+		b := current.String()
+		b = b[:literal] + strings.TrimRightFunc(b[literal:], unicode.IsSpace)
+		if b == "" && !quoted {
+			bounds = append(bounds, nil)
+		} else {
+			bounds = append(bounds, &b)
+		}
+		current.Reset()
+		quoted, literal = false, 0
+	}
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s):
+			i++
+			current.WriteByte(s[i])
+			literal = current.Len()
+		case c == '"':
+			if inQuote && i+1 < len(s) && s[i+1] == '"' {
+				// "" inside quotes is a literal quote
+				i++
+				current.WriteByte('"')
+				literal = current.Len()
+				continue
+			}
+			inQuote, quoted = !inQuote, true
+			literal = current.Len()
+		case c == ',' && !inQuote:
+			flush()
+		case inQuote:
+			current.WriteByte(c)
+			literal = current.Len()
+		case current.Len() == 0 && unicode.IsSpace(rune(c)):
+			// leading whitespace of an unquoted bound
+		default:
+			current.WriteByte(c)
+		}
+	}
+	if inQuote {
+		return nil, nil, fmt.Errorf("%w: unterminated quote in range literal", ErrInvalidFormat)
+	}
+	flush()
+
+	if len(bounds) != 2 {
+		return nil, nil, fmt.Errorf("%w: range literal must have exactly two bounds", ErrInvalidFormat)
+	}
+	return bounds[0], bounds[1], nil
+}
+
+// rangeBound converts a Go value to a range bound
+// @param v any - The bound value, nil or null for unbounded
+// @param inclusive bool - True if the bound is inclusive
+// @return T - The converted bound
+// @return pgtype.BoundType - Inclusive, Exclusive or Unbounded
+// @return error - The conversion error, if any
+func rangeBound[T RangeElement](v any, inclusive bool) (T, pgtype.BoundType, error) {
+	val, err := SetField[T](v)
+	if err != nil {
+		return val, pgtype.Unbounded, err
+	}
+	if _, valid, _ := scalarValue(val); !valid {
+		return val, pgtype.Unbounded, nil
+	}
+	if inclusive {
+		return val, pgtype.Inclusive, nil
+	}
+	return val, pgtype.Exclusive, nil
+}
+
+// checkRange validates the bounds of a range and returns it in canonical form
+// @param r pgtype.Range[T] - The range to check
+// @return pgtype.Range[T] - The canonical range
+// @return error - ErrInvalidFormat if lower is greater than upper, ErrOverflow if a discrete bound overflows
+func checkRange[T RangeElement](r pgtype.Range[T]) (pgtype.Range[T], error) {
+	if !r.Valid || r.LowerType == pgtype.Empty {
+		return r, nil
+	}
+	if r.LowerType != pgtype.Unbounded && r.UpperType != pgtype.Unbounded && compareRangeElements(r.Lower, r.Upper) > 0 {
+		return pgtype.Range[T]{}, fmt.Errorf("%w: range lower bound must be less than or equal to range upper bound", ErrInvalidFormat)
+	}
+	return canonicalRange(r)
+}
+
+// canonicalRange converts discrete ranges to "[)" form and ranges without values to the empty range, like PostgreSQL
+// @param r pgtype.Range[T] - The valid range
+// @return pgtype.Range[T] - The canonical range
+// @return error - ErrOverflow if a discrete bound cannot be incremented
+func canonicalRange[T RangeElement](r pgtype.Range[T]) (pgtype.Range[T], error) {
+	if !r.Valid || r.LowerType == pgtype.Empty || r.UpperType == pgtype.Empty {
+		return r, nil
+	}
+
+	var err error
+	if r.LowerType == pgtype.Exclusive {
+		if next, discrete, nextErr := nextDiscrete(r.Lower); discrete {
+			r.Lower, r.LowerType, err = next, pgtype.Inclusive, nextErr
+		}
+	}
+	if r.UpperType == pgtype.Inclusive && err == nil {
+		if next, discrete, nextErr := nextDiscrete(r.Upper); discrete {
+			r.Upper, r.UpperType, err = next, pgtype.Exclusive, nextErr
+		}
+	}
+	if err != nil {
+		return r, err
+	}
+
+	if r.LowerType != pgtype.Unbounded && r.UpperType != pgtype.Unbounded {
+		c := compareRangeElements(r.Lower, r.Upper)
+		if c > 0 || (c == 0 && (r.LowerType != pgtype.Inclusive || r.UpperType != pgtype.Inclusive)) {
+			return pgtype.Range[T]{LowerType: pgtype.Empty, UpperType: pgtype.Empty, Valid: true}, nil
+		}
+	}
+	return r, nil
+}
+
+// nextDiscrete returns the next value of a discrete range element
+// @param v T - The value
+// @return T - The next value
+// @return bool - False if T is not discrete (numeric, timestamp) or the value is infinite
+// @return error - ErrOverflow if the value is the maximum of its type
+func nextDiscrete[T RangeElement](v T) (T, bool, error) {
+	switch val := any(v).(type) {
+	case pgtype.Int4:
+		if val.Int32 == math.MaxInt32 {
+			return v, true, ErrOverflow
+		}
+		val.Int32++
+		return any(val).(T), true, nil
+	case pgtype.Int8:
+		if val.Int64 == math.MaxInt64 {
+			return v, true, ErrOverflow
+		}
+		val.Int64++
+		return any(val).(T), true, nil
+	case pgtype.Date:
+		if val.InfinityModifier != pgtype.Finite {
+			return v, false, nil
+		}
+		val.Time = val.Time.AddDate(0, 0, 1)
+		return any(val).(T), true, nil
+	}
+	return v, false, nil
+}
+
+// compareRangeElements compares two valid range elements
+// Infinite dates and timestamps sort before or after every finite value, NaN sorts after every number like PostgreSQL
+// @param a T - The first element
+// @param b T - The second element
+// @return int - -1 if a < b, 0 if a == b, 1 if a > b
+func compareRangeElements[T RangeElement](a, b T) int {
+	switch x := any(a).(type) {
+	case pgtype.Int4:
+		return cmp.Compare(x.Int32, any(b).(pgtype.Int4).Int32)
+	case pgtype.Int8:
+		return cmp.Compare(x.Int64, any(b).(pgtype.Int8).Int64)
+	case pgtype.Numeric:
+		y := any(b).(pgtype.Numeric)
+		if c := cmp.Compare(numericOrder(x), numericOrder(y)); c != 0 || x.NaN || x.InfinityModifier != pgtype.Finite {
+			return c
+		}
+		rx, _ := numericToRat(x)
+		ry, _ := numericToRat(y)
+		return rx.Cmp(ry)
+	case pgtype.Date:
+		y := any(b).(pgtype.Date)
+		if c := cmp.Compare(x.InfinityModifier, y.InfinityModifier); c != 0 || x.InfinityModifier != pgtype.Finite {
+			return c
+		}
+		return x.Time.Compare(y.Time)
+	case pgtype.Timestamp:
+		y := any(b).(pgtype.Timestamp)
+		if c := cmp.Compare(x.InfinityModifier, y.InfinityModifier); c != 0 || x.InfinityModifier != pgtype.Finite {
+			return c
+		}
+		return x.Time.Compare(y.Time)
+	case pgtype.Timestamptz:
+		y := any(b).(pgtype.Timestamptz)
+		if c := cmp.Compare(x.InfinityModifier, y.InfinityModifier); c != 0 || x.InfinityModifier != pgtype.Finite {
+			return c
+		}
+		return x.Time.Compare(y.Time)
+	}
+	return 0
+}
+
+// numericOrder ranks a numeric by kind: -infinity, finite, infinity, NaN
+// @param n pgtype.Numeric - The numeric
+// @return int - The rank
+func numericOrder(n pgtype.Numeric) int {
+	switch {
+	case n.NaN:
+		return 2
+	case n.InfinityModifier == pgtype.NegativeInfinity:
+		return -1
+	case n.InfinityModifier == pgtype.Infinity:
+		return 1
+	}
+	return 0
+}
+
+// boundInfo is a range bound used by the predicates, following PostgreSQL's RangeBound
+type boundInfo[T RangeElement] struct {
+	val       T
+	infinite  bool
+	inclusive bool
+	lower     bool
+}
+
+// rangeBounds returns the lower and upper bound of a non-empty range
+// @param r pgtype.Range[T] - The range
+// @return boundInfo[T] - The lower bound
+// @return boundInfo[T] - The upper bound
+func rangeBounds[T RangeElement](r pgtype.Range[T]) (boundInfo[T], boundInfo[T]) {
+	return boundInfo[T]{val: r.Lower, infinite: r.LowerType == pgtype.Unbounded, inclusive: r.LowerType == pgtype.Inclusive, lower: true},
+		boundInfo[T]{val: r.Upper, infinite: r.UpperType == pgtype.Unbounded, inclusive: r.UpperType == pgtype.Inclusive}
+}
+
+// compareBounds compares two bounds, a port of PostgreSQL's range_cmp_bounds
+// @param b1 boundInfo[T] - The first bound
+// @param b2 boundInfo[T] - The second bound
+// @return int - -1 if b1 < b2, 0 if b1 == b2, 1 if b1 > b2
+func compareBounds[T RangeElement](b1, b2 boundInfo[T]) int {
+	switch {
+	case b1.infinite && b2.infinite:
+		if b1.lower == b2.lower {
+			return 0
+		}
+		return funcvx.Ternary(b1.lower, -1, 1)
+	case b1.infinite:
+		return funcvx.Ternary(b1.lower, -1, 1)
+	case b2.infinite:
+		return funcvx.Ternary(b2.lower, 1, -1)
+	}
+
+	result := compareRangeElements(b1.val, b2.val)
+	if result == 0 {
+		switch {
+		case !b1.inclusive && !b2.inclusive:
+			if b1.lower == b2.lower {
+				return 0
+			}
+			return funcvx.Ternary(b1.lower, 1, -1)
+		case !b1.inclusive:
+			return funcvx.Ternary(b1.lower, 1, -1)
+		case !b2.inclusive:
+			return funcvx.Ternary(b2.lower, -1, 1)
+		}
+	}
+	return result
+}
+
+// usableRange canonicalizes a range for the predicates
+// @param r pgtype.Range[T] - The range
+// @return pgtype.Range[T] - The canonical range
+// @return bool - False if the range is NULL or empty
+func usableRange[T RangeElement](r pgtype.Range[T]) (pgtype.Range[T], bool) {
+	if !r.Valid {
+		return r, false
+	}
+	if c, err := canonicalRange(r); err == nil {
+		r = c
+	}
+	return r, r.LowerType != pgtype.Empty
+}
+
+// RangeContains reports whether a range contains a value, like PostgreSQL's @> operator
+// NULL and empty ranges contain nothing
+// @param r pgtype.Range[T] - The range
+// @param v T - The value
+// @return bool - True if v is inside r
+func RangeContains[T RangeElement](r pgtype.Range[T], v T) bool {
+	r, ok := usableRange(r)
+	if !ok {
+		return false
+	}
+	if r.LowerType != pgtype.Unbounded {
+		c := compareRangeElements(r.Lower, v)
+		if c > 0 || (c == 0 && r.LowerType != pgtype.Inclusive) {
+			return false
+		}
+	}
+	if r.UpperType != pgtype.Unbounded {
+		c := compareRangeElements(r.Upper, v)
+		if c < 0 || (c == 0 && r.UpperType != pgtype.Inclusive) {
+			return false
+		}
+	}
+	return true
+}
+
+// RangeOverlaps reports whether two ranges have a value in common, like PostgreSQL's && operator
+// @param a pgtype.Range[T] - The first range
+// @param b pgtype.Range[T] - The second range
+// @return bool - True if the ranges overlap
+func RangeOverlaps[T RangeElement](a, b pgtype.Range[T]) bool {
+	a, okA := usableRange(a)
+	b, okB := usableRange(b)
+	if !okA || !okB {
+		return false
+	}
+	lower1, upper1 := rangeBounds(a)
+	lower2, upper2 := rangeBounds(b)
+
+	if compareBounds(lower1, lower2) >= 0 && compareBounds(lower1, upper2) <= 0 {
+		return true
+	}
+	return compareBounds(lower2, lower1) >= 0 && compareBounds(lower2, upper1) <= 0
+}
+
+// RangeAdjacent reports whether two ranges touch without overlapping, like PostgreSQL's -|- operator
+// e.g. [2024-01-01,2024-02-01) and [2024-02-01,2024-03-01) are adjacent
+// @param a pgtype.Range[T] - The first range
+// @param b pgtype.Range[T] - The second range
+// @return bool - True if the ranges are adjacent
+func RangeAdjacent[T RangeElement](a, b pgtype.Range[T]) bool {
+	a, okA := usableRange(a)
+	b, okB := usableRange(b)
+	if !okA || !okB {
+		return false
+	}
+	lower1, upper1 := rangeBounds(a)
+	lower2, upper2 := rangeBounds(b)
+	return boundsAdjacent(upper1, lower2) || boundsAdjacent(upper2, lower1)
+}
+
+// boundsAdjacent reports whether an upper bound and a lower bound touch, discrete ranges must be canonical
+// @param upper boundInfo[T] - The upper bound
+// @param lower boundInfo[T] - The lower bound
+// @return bool - True if nothing lies between the bounds and they do not overlap
+func boundsAdjacent[T RangeElement](upper, lower boundInfo[T]) bool {
+	if upper.infinite || lower.infinite {
+		return false
+	}
+	return compareRangeElements(upper.val, lower.val) == 0 && upper.inclusive != lower.inclusive
+}
+
+// RangeIntersect returns the values two ranges have in common, like PostgreSQL's * operator
+// It returns the empty range if the ranges do not overlap, and a NULL range if either range is NULL
+// @param a pgtype.Range[T] - The first range
+// @param b pgtype.Range[T] - The second range
+// @return pgtype.Range[T] - The intersection
+func RangeIntersect[T RangeElement](a, b pgtype.Range[T]) pgtype.Range[T] {
+	if !a.Valid || !b.Valid {
+		return pgtype.Range[T]{}
+	}
+	empty := pgtype.Range[T]{LowerType: pgtype.Empty, UpperType: pgtype.Empty, Valid: true}
+	if !RangeOverlaps(a, b) {
+		return empty
+	}
+
+	a, _ = usableRange(a)
+	b, _ = usableRange(b)
+	out := a
+	lower1, upper1 := rangeBounds(a)
+	lower2, upper2 := rangeBounds(b)
+	if compareBounds(lower2, lower1) > 0 {
+		out.Lower, out.LowerType = b.Lower, b.LowerType
+	}
+	if compareBounds(upper2, upper1) < 0 {
+		out.Upper, out.UpperType = b.Upper, b.UpperType
+	}
+
+	if c, err := canonicalRange(out); err == nil {
+		return c
+	}
+	return out
+}
@@ -1,27 +1,122 @@
 package pgxhelpers
 
 import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/hex"
+	"math"
+	"math/big"
+	"net"
+	"net/netip"
+	"reflect"
 	"time"
 
+	"github.com/ChungNQ511/vnw-helpers/datecvx"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+// Every revert accepts the pgtype value, a pointer to it and driver.Valuer inputs such as sql.NullString or Nullable[T]
+// NULL and nil pointers are returned as the zero value, the E variants return ErrUnsupportedType for other inputs
+
+// pgtypePkgPath is the package path of the pgtype structs, which are passed to the reverts as is
+var pgtypePkgPath = reflect.TypeOf(pgtype.Text{}).PkgPath()
+
+// revertSource unwraps the input of a revert function
+// Pointers are dereferenced and driver.Valuer inputs other than pgtype structs are replaced by their Value
+// @param v any - The input of the revert function
+// @return any - The unwrapped value, nil for NULL
+// @return bool - True if the value was returned by a driver.Valuer
+// @return error - The error of the Value method, if any
+func revertSource(v any) (any, bool, error) {
+	for v != nil {
+		rv := reflect.ValueOf(v)
+		isPointer := rv.Kind() == reflect.Pointer
+		if isPointer && rv.IsNil() {
+			return nil, false, nil
+		}
+
+		base := rv.Type()
+		for base.Kind() == reflect.Pointer {
+			base = base.Elem()
+		}
+		if valuer, ok := v.(driver.Valuer); ok && base.PkgPath() != pgtypePkgPath {
+			dv, err := valuer.Value()
+			if err != nil {
+				return nil, false, err
+			}
+			return dv, dv != nil, nil
+		}
+
+		if !isPointer {
+			return v, false, nil
+		}
+		v = rv.Elem().Interface()
+	}
+	return nil, false, nil
+}
+
+// revertPg unwraps the input of a revert function and returns it as the pgtype P
+// P values are returned as is, NULL as the zero P, and values returned by a driver.Valuer go through conv
+// Other inputs return a *ConvertError with ErrUnsupportedType and the Go type G as target
+// @param v any - The input of the revert function
+// @param conv func(any) (P, error) - The strict converter for values returned by a driver.Valuer
+// @return P - The pgtype value
+// @return error - The conversion error, if any
+func revertPg[P, G any](v any, conv func(any) (P, error)) (P, error) {
+	var zero P
+	src, fromValuer, err := revertSource(v)
+	if err != nil {
+		return zero, newConvertError[G](v, err)
+	}
+	if src == nil {
+		return zero, nil
+	}
+	if p, ok := src.(P); ok {
+		return p, nil
+	}
+	if fromValuer {
+		return conv(src)
+	}
+	return zero, newConvertError[G](v, ErrUnsupportedType)
+}
+
 // RevertPgText reverts a pgtype.Text to a string
 // It's useful for converting a pgtype.Text to a string
 // @param v any - The value to convert to a string
 // @return string - The converted string
 func RevertPgText(v any) string {
-	switch val := v.(type) {
-	case pgtype.Text:
-		if val.Valid {
-			return val.String
-		}
-	case *pgtype.Text:
-		if val != nil && val.Valid {
-			return val.String
+	s, _, _ := textFromAny(v)
+	return s
+}
+
+// RevertPgTextE is the strict variant of RevertPgText
+// It returns a *ConvertError when the value is not a pgtype.Text, a pointer to one or a driver.Valuer
+// @param v any - The value to convert to a string
+// @return string - The converted string, "" for NULL
+// @return error - The conversion error, if any
+func RevertPgTextE(v any) (string, error) {
+	s, _, err := textFromAny(v)
+	return s, err
+}
+
+// textFromAny extracts the string of a valid pgtype.Text
+// @param v any - The value to extract the string from
+// @return string - The extracted string
+// @return bool - False if the value is NULL
+// @return error - The conversion error, if any
+func textFromAny(v any) (string, bool, error) {
+	t, err := revertPg[pgtype.Text, string](v, func(src any) (pgtype.Text, error) {
+		// an empty string from a driver.Valuer is a value, not NULL
+		if s, ok := src.(string); ok {
+			return pgtype.Text{String: s, Valid: true}, nil
 		}
+		return SetTextFieldE(src)
+	})
+	if err != nil || !t.Valid {
+		return "", false, err
 	}
-	return ""
+	return t.String, true, nil
 }
 
 // RevertPgDate reverts a pgtype.Date to a time.Time
@@ -29,13 +124,31 @@ func RevertPgText(v any) string {
 // @param v any - The value to convert to a time.Time
 // @return time.Time - The converted time.Time
 func RevertPgDate(v any) time.Time {
-	switch val := v.(type) {
-	case pgtype.Date:
-		if val.Valid {
-			return val.Time
-		}
+	t, _, _ := dateFromAny(v)
+	return t
+}
+
+// RevertPgDateE is the strict variant of RevertPgDate
+// It returns a *ConvertError when the value is not a pgtype.Date, a pointer to one or a driver.Valuer
+// @param v any - The value to convert to a time.Time
+// @return time.Time - The converted time.Time, the zero time for NULL
+// @return error - The conversion error, if any
+func RevertPgDateE(v any) (time.Time, error) {
+	t, _, err := dateFromAny(v)
+	return t, err
+}
+
+// dateFromAny extracts the time of a valid pgtype.Date
+// @param v any - The value to extract the time from
+// @return time.Time - The extracted time
+// @return bool - False if the value is NULL
+// @return error - The conversion error, if any
+func dateFromAny(v any) (time.Time, bool, error) {
+	d, err := revertPg[pgtype.Date, time.Time](v, SetDateFieldE)
+	if err != nil || !d.Valid {
+		return time.Time{}, false, err
 	}
-	return time.Time{}
+	return d.Time, true, nil
 }
 
 // RevertPgTimestamp reverts a pgtype.Timestamp to a time.Time
@@ -43,13 +156,31 @@ func RevertPgDate(v any) time.Time {
 // @param v any - The value to convert to a time.Time
 // @return time.Time - The converted time.Time
 func RevertPgTimestamp(v any) time.Time {
-	switch val := v.(type) {
-	case pgtype.Timestamp:
-		if val.Valid {
-			return val.Time
-		}
+	t, _, _ := timestampFromAny(v)
+	return t
+}
+
+// RevertPgTimestampE is the strict variant of RevertPgTimestamp
+// It returns a *ConvertError when the value is not a pgtype.Timestamp, a pointer to one or a driver.Valuer
+// @param v any - The value to convert to a time.Time
+// @return time.Time - The converted time.Time, the zero time for NULL
+// @return error - The conversion error, if any
+func RevertPgTimestampE(v any) (time.Time, error) {
+	t, _, err := timestampFromAny(v)
+	return t, err
+}
+
+// timestampFromAny extracts the time of a valid pgtype.Timestamp
+// @param v any - The value to extract the time from
+// @return time.Time - The extracted time
+// @return bool - False if the value is NULL
+// @return error - The conversion error, if any
+func timestampFromAny(v any) (time.Time, bool, error) {
+	ts, err := revertPg[pgtype.Timestamp, time.Time](v, SetTimestampFieldE)
+	if err != nil || !ts.Valid {
+		return time.Time{}, false, err
 	}
-	return time.Time{}
+	return ts.Time, true, nil
 }
 
 // RevertPgTimestamptz reverts a pgtype.Timestamptz to a time.Time
@@ -57,13 +188,31 @@ func RevertPgTimestamp(v any) time.Time {
 // @param v any - The value to convert to a time.Time
 // @return time.Time - The converted time.Time
 func RevertPgTimestamptz(v any) time.Time {
-	switch val := v.(type) {
-	case pgtype.Timestamptz:
-		if val.Valid {
-			return val.Time
-		}
+	t, _, _ := timestamptzFromAny(v)
+	return t
+}
+
+// RevertPgTimestamptzE is the strict variant of RevertPgTimestamptz
+// It returns a *ConvertError when the value is not a pgtype.Timestamptz, a pointer to one or a driver.Valuer
+// @param v any - The value to convert to a time.Time
+// @return time.Time - The converted time.Time, the zero time for NULL
+// @return error - The conversion error, if any
+func RevertPgTimestamptzE(v any) (time.Time, error) {
+	t, _, err := timestamptzFromAny(v)
+	return t, err
+}
+
+// timestamptzFromAny extracts the time of a valid pgtype.Timestamptz
+// @param v any - The value to extract the time from
+// @return time.Time - The extracted time
+// @return bool - False if the value is NULL
+// @return error - The conversion error, if any
+func timestamptzFromAny(v any) (time.Time, bool, error) {
+	ts, err := revertPg[pgtype.Timestamptz, time.Time](v, SetTimestamptzFieldE)
+	if err != nil || !ts.Valid {
+		return time.Time{}, false, err
 	}
-	return time.Time{}
+	return ts.Time, true, nil
 }
 
 // RevertPgBool reverts a pgtype.Bool to a bool
@@ -71,51 +220,804 @@ func RevertPgTimestamptz(v any) time.Time {
 // @param v any - The value to convert to a bool
 // @return bool - The converted bool
 func RevertPgBool(v any) bool {
-	switch val := v.(type) {
-	case pgtype.Bool:
-		if val.Valid {
-			return val.Bool
-		}
+	b, _, _ := boolFromAny(v)
+	return b
+}
+
+// RevertPgBoolE is the strict variant of RevertPgBool
+// It returns a *ConvertError when the value is not a pgtype.Bool, a pointer to one or a driver.Valuer
+// @param v any - The value to convert to a bool
+// @return bool - The converted bool, false for NULL
+// @return error - The conversion error, if any
+func RevertPgBoolE(v any) (bool, error) {
+	b, _, err := boolFromAny(v)
+	return b, err
+}
+
+// boolFromAny extracts the bool of a valid pgtype.Bool
+// @param v any - The value to extract the bool from
+// @return bool - The extracted bool
+// @return bool - False if the value is NULL
+// @return error - The conversion error, if any
+func boolFromAny(v any) (bool, bool, error) {
+	b, err := revertPg[pgtype.Bool, bool](v, SetBoolFieldE)
+	if err != nil || !b.Valid {
+		return false, false, err
 	}
-	return false
+	return b.Bool, true, nil
 }
 
-// RevertFloatField reverts a pgtype.Float4 or pgtype.Float8 to a float64
+// PgFloat lists the float inputs accepted by RevertFloatField: pgtype floats, sql.NullFloat64, Nullable floats and pointers to them
+type PgFloat interface {
+	pgtype.Float4 | pgtype.Float8 | sql.NullFloat64 | Nullable[float32] | Nullable[float64] |
+		*pgtype.Float4 | *pgtype.Float8 | *sql.NullFloat64 | *Nullable[float32] | *Nullable[float64]
+}
+
+// RevertFloatField reverts a pgtype.Float4 or pgtype.Float8, a sql.NullFloat64, a Nullable float or a pointer to one to a float64
+// NULL is returned as 0
 // It's useful for converting a pgtype.Float4 or pgtype.Float8 to a float64
 // @param v T - The value to convert to a float64
 // @return float64 - The converted float64
-func RevertFloatField[T pgtype.Float4 | pgtype.Float8](v T) float64 {
-	switch val := any(v).(type) {
+func RevertFloatField[T PgFloat](v T) float64 {
+	f, _ := RevertFloatFieldE(v)
+	return f
+}
+
+// RevertFloatFieldE is the strict variant of RevertFloatField
+// It returns a *ConvertError when the value of a sql.NullFloat64 or Nullable cannot be converted
+// @param v T - The value to convert to a float64
+// @return float64 - The converted float64, 0 for NULL
+// @return error - The conversion error, if any
+func RevertFloatFieldE[T PgFloat](v T) (float64, error) {
+	f, _, err := pgFloatFromAny(v)
+	return f, err
+}
+
+// pgFloatFromAny extracts the float64 of a valid pgtype.Float4 or pgtype.Float8
+// @param v any - The value to extract the float64 from
+// @return float64 - The extracted float64
+// @return bool - False if the value is NULL
+// @return error - The conversion error, if any
+func pgFloatFromAny(v any) (float64, bool, error) {
+	src, fromValuer, err := revertSource(v)
+	if err != nil {
+		return 0, false, newConvertError[float64](v, err)
+	}
+	switch val := src.(type) {
+	case nil:
+		return 0, false, nil
 	case pgtype.Float4:
 		if val.Valid {
-			return float64(val.Float32)
+			return float64(val.Float32), true, nil
 		}
+		return 0, false, nil
 	case pgtype.Float8:
 		if val.Valid {
-			return val.Float64
+			return val.Float64, true, nil
 		}
+		return 0, false, nil
 	}
-	return 0
+	if fromValuer {
+		f, err := SetFloatFieldE[pgtype.Float8](src)
+		return f.Float64, f.Valid, err
+	}
+	return 0, false, newConvertError[float64](v, ErrUnsupportedType)
+}
+
+// PgInt lists the integer inputs accepted by RevertIntField: pgtype integers, sql.NullInt16/32/64, Nullable integers and pointers to them
+type PgInt interface {
+	pgtype.Int2 | pgtype.Int4 | pgtype.Int8 | sql.NullInt16 | sql.NullInt32 | sql.NullInt64 |
+		Nullable[int] | Nullable[int16] | Nullable[int32] | Nullable[int64] |
+		*pgtype.Int2 | *pgtype.Int4 | *pgtype.Int8 | *sql.NullInt16 | *sql.NullInt32 | *sql.NullInt64 |
+		*Nullable[int] | *Nullable[int16] | *Nullable[int32] | *Nullable[int64]
 }
 
-// RevertIntField reverts a pgtype.Int2 or pgtype.Int4 or pgtype.Int8 to an int
+// RevertIntField reverts a pgtype.Int2 or pgtype.Int4 or pgtype.Int8, a sql.NullInt16/32/64, a Nullable integer or a pointer to one to an int64
+// NULL is returned as 0
 // It's useful for converting a pgtype.Int2 or pgtype.Int4 or pgtype.Int8 to an int
-// @param v T - The value to convert to an int
-// @return int - The converted int
-func RevertIntField[T pgtype.Int2 | pgtype.Int4 | pgtype.Int8](v T) int64 {
-	switch val := any(v).(type) {
+// @param v T - The value to convert to an int64
+// @return int64 - The converted int64
+func RevertIntField[T PgInt](v T) int64 {
+	n, _ := RevertIntFieldE(v)
+	return n
+}
+
+// RevertIntFieldE is the strict variant of RevertIntField
+// It returns a *ConvertError when the value of a sql.NullInt16/32/64 or Nullable cannot be converted
+// @param v T - The value to convert to an int64
+// @return int64 - The converted int64, 0 for NULL
+// @return error - The conversion error, if any
+func RevertIntFieldE[T PgInt](v T) (int64, error) {
+	n, _, err := pgIntFromAny(v)
+	return n, err
+}
+
+// pgIntFromAny extracts the int64 of a valid pgtype.Int2, pgtype.Int4 or pgtype.Int8
+// @param v any - The value to extract the int64 from
+// @return int64 - The extracted int64
+// @return bool - False if the value is NULL
+// @return error - The conversion error, if any
+func pgIntFromAny(v any) (int64, bool, error) {
+	src, fromValuer, err := revertSource(v)
+	if err != nil {
+		return 0, false, newConvertError[int64](v, err)
+	}
+	switch val := src.(type) {
+	case nil:
+		return 0, false, nil
 	case pgtype.Int2:
 		if val.Valid {
-			return int64(val.Int16)
+			return int64(val.Int16), true, nil
 		}
+		return 0, false, nil
 	case pgtype.Int4:
 		if val.Valid {
-			return int64(val.Int32)
+			return int64(val.Int32), true, nil
 		}
+		return 0, false, nil
 	case pgtype.Int8:
 		if val.Valid {
-			return val.Int64
+			return val.Int64, true, nil
 		}
+		return 0, false, nil
+	}
+	if fromValuer {
+		n, err := SetIntFieldE[pgtype.Int8](src)
+		return n.Int64, n.Valid, err
+	}
+	return 0, false, newConvertError[int64](v, ErrUnsupportedType)
+}
+
+// RevertNumericField reverts a pgtype.Numeric to an exact decimal string
+// NaN and infinity are returned as "NaN", "Infinity" and "-Infinity", NULL is returned as ""
+// It's useful for converting a pgtype.Numeric to a string without losing precision
+// @param v any - The value to convert to a string
+// @return string - The converted string
+func RevertNumericField(v any) string {
+	s, _ := RevertNumericFieldE(v)
+	return s
+}
+
+// RevertNumericFieldE is the strict variant of RevertNumericField
+// It returns a *ConvertError when the value is not a pgtype.Numeric, a pointer to one or a driver.Valuer
+// @param v any - The value to convert to a string
+// @return string - The converted string, "" for NULL
+// @return error - The conversion error, if any
+func RevertNumericFieldE(v any) (string, error) {
+	n, ok, err := numericFromAny(v)
+	if !ok {
+		return "", err
+	}
+	return numericString(n), nil
+}
+
+// RevertNumericRat reverts a pgtype.Numeric to an exact *big.Rat
+// It returns nil for NULL, ErrNonFinite for NaN and infinity and ErrUnsupportedType for other inputs
+// It's useful for doing exact arithmetic on a pgtype.Numeric
+// @param v any - The value to convert to a *big.Rat
+// @return *big.Rat - The converted *big.Rat
+// @return error - The conversion error, if any
+func RevertNumericRat(v any) (*big.Rat, error) {
+	n, ok, err := numericFromAny(v)
+	if !ok {
+		return nil, err
+	}
+	return numericToRat(n)
+}
+
+// RevertNumericFloat reverts a pgtype.Numeric to a float64
+// NaN and infinity are returned as math.NaN() and math.Inf(), NULL and unsupported inputs are returned as 0
+// It's useful for converting a pgtype.Numeric to a float64 when an approximation is acceptable
+// @param v any - The value to convert to a float64
+// @return float64 - The converted float64
+// @return bool - True if the float64 is not exactly equal to the numeric value (precision was lost)
+func RevertNumericFloat(v any) (float64, bool) {
+	f, lossy, _ := RevertNumericFloatE(v)
+	return f, lossy
+}
+
+// RevertNumericFloatE is the strict variant of RevertNumericFloat
+// It returns a *ConvertError when the value is not a pgtype.Numeric, a pointer to one or a driver.Valuer
+// @param v any - The value to convert to a float64
+// @return float64 - The converted float64, 0 for NULL
+// @return bool - True if the float64 is not exactly equal to the numeric value (precision was lost)
+// @return error - The conversion error, if any
+func RevertNumericFloatE(v any) (float64, bool, error) {
+	n, ok, err := numericFromAny(v)
+	if !ok {
+		return 0, false, err
+	}
+	switch {
+	case n.NaN:
+		return math.NaN(), false, nil
+	case n.InfinityModifier == pgtype.Infinity:
+		return math.Inf(1), false, nil
+	case n.InfinityModifier == pgtype.NegativeInfinity:
+		return math.Inf(-1), false, nil
+	}
+
+	r, err := numericToRat(n)
+	if err != nil {
+		return 0, false, newConvertError[float64](v, err)
+	}
+	f, exact := r.Float64()
+	return f, !exact, nil
+}
+
+// RevertNumericInt reverts a pgtype.Numeric to an int64
+// It returns ErrFractional if the value has a fractional part, ErrOverflow if it does not fit in an int64,
+// ErrNonFinite for NaN and infinity and ErrUnsupportedType for other inputs, NULL is returned as 0
+// It's useful for converting a pgtype.Numeric that holds a whole number to an int64
+// @param v any - The value to convert to an int64
+// @return int64 - The converted int64
+// @return error - The conversion error, if any
+func RevertNumericInt(v any) (int64, error) {
+	n, ok, err := numericFromAny(v)
+	if !ok {
+		return 0, err
+	}
+	return numericToInt64(n)
+}
+
+// numericFromAny extracts a valid pgtype.Numeric
+// @param v any - The value to extract the pgtype.Numeric from
+// @return pgtype.Numeric - The extracted pgtype.Numeric
+// @return bool - False if the value is NULL or cannot be converted
+// @return error - The conversion error, if any
+func numericFromAny(v any) (pgtype.Numeric, bool, error) {
+	n, err := revertPg[pgtype.Numeric, pgtype.Numeric](v, func(src any) (pgtype.Numeric, error) {
+		return SetNumericFieldE(src)
+	})
+	if err != nil || !n.Valid {
+		return pgtype.Numeric{}, false, err
+	}
+	return n, true, nil
+}
+
+// RevertPgUUID reverts a pgtype.UUID to a canonical lowercase uuid string
+// It's useful for converting a pgtype.UUID to a string
+// @param v any - The value to convert to a string
+// @return string - The converted string, or "" for NULL
+func RevertPgUUID(v any) string {
+	s, _ := RevertPgUUIDE(v)
+	return s
+}
+
+// RevertPgUUIDE is the strict variant of RevertPgUUID
+// It returns a *ConvertError when the value is not a pgtype.UUID, a pointer to one or a driver.Valuer
+// @param v any - The value to convert to a string
+// @return string - The converted string, "" for NULL
+// @return error - The conversion error, if any
+func RevertPgUUIDE(v any) (string, error) {
+	b, ok, err := uuidFromAny(v)
+	if !ok {
+		return "", err
+	}
+	return formatUUID(b), nil
+}
+
+// RevertPgUUIDBytes reverts a pgtype.UUID to its 16 bytes
+// It's useful for converting a pgtype.UUID to a [16]byte or a uuid library type based on it
+// @param v any - The value to convert to a [16]byte
+// @return [16]byte - The converted [16]byte, or zero bytes for NULL
+func RevertPgUUIDBytes(v any) [16]byte {
+	b, _, _ := uuidFromAny(v)
+	return b
+}
+
+// RevertPgUUIDBytesE is the strict variant of RevertPgUUIDBytes
+// @param v any - The value to convert to a [16]byte
+// @return [16]byte - The converted [16]byte, zero bytes for NULL
+// @return error - The conversion error, if any
+func RevertPgUUIDBytesE(v any) ([16]byte, error) {
+	b, _, err := uuidFromAny(v)
+	return b, err
+}
+
+// uuidFromAny extracts the bytes of a valid pgtype.UUID
+// @param v any - The value to extract the bytes from
+// @return [16]byte - The extracted bytes
+// @return bool - False if the value is NULL
+// @return error - The conversion error, if any
+func uuidFromAny(v any) ([16]byte, bool, error) {
+	u, err := revertPg[pgtype.UUID, string](v, SetUUIDFieldE)
+	if err != nil || !u.Valid {
+		return [16]byte{}, false, err
+	}
+	return u.Bytes, true, nil
+}
+
+// RevertPgInterval reverts a pgtype.Interval to a time.Duration
+// Days are counted as 24 hours, NULL is returned as 0
+// It returns ErrAmbiguousInterval if the interval has months, ErrOverflow if it does not fit in a time.Duration
+// and ErrUnsupportedType for inputs that are not an interval
+// It's useful for converting an interval of days, hours and minutes to a time.Duration
+// @param v any - The value to convert to a time.Duration
+// @return time.Duration - The converted time.Duration
+// @return error - The conversion error, if any
+func RevertPgInterval(v any) (time.Duration, error) {
+	iv, ok, err := intervalFromAny(v)
+	if !ok {
+		return 0, err
+	}
+	if iv.Months != 0 {
+		return 0, newConvertError[time.Duration](v, ErrAmbiguousInterval)
+	}
+
+	// check the bounds before multiplying, days * microsPerDay alone can overflow an int64
+	const limit = math.MaxInt64 / int64(time.Microsecond)
+	days, micros := int64(iv.Days), iv.Microseconds
+	if micros > limit || micros < -limit ||
+		(days > 0 && days > (limit-micros)/microsPerDay) || (days < 0 && days < (-limit-micros)/microsPerDay) {
+		return 0, newConvertError[time.Duration](v, ErrOverflow)
+	}
+	return time.Duration(days*microsPerDay+micros) * time.Microsecond, nil
+}
+
+// RevertPgIntervalParts reverts a pgtype.Interval to its months, days and microseconds
+// NULL is returned as zero IntervalParts
+// It's useful for intervals with months, which have no fixed time.Duration
+// @param v any - The value to convert to IntervalParts
+// @return IntervalParts - The converted IntervalParts
+func RevertPgIntervalParts(v any) IntervalParts {
+	parts, _ := RevertPgIntervalPartsE(v)
+	return parts
+}
+
+// RevertPgIntervalPartsE is the strict variant of RevertPgIntervalParts
+// It returns a *ConvertError when the value is not a pgtype.Interval, a pointer to one or a driver.Valuer
+// @param v any - The value to convert to IntervalParts
+// @return IntervalParts - The converted IntervalParts, zero for NULL
+// @return error - The conversion error, if any
+func RevertPgIntervalPartsE(v any) (IntervalParts, error) {
+	iv, _, err := intervalFromAny(v)
+	return IntervalParts{Months: iv.Months, Days: iv.Days, Microseconds: iv.Microseconds}, err
+}
+
+// intervalFromAny extracts a valid pgtype.Interval
+// @param v any - The value to extract the pgtype.Interval from
+// @return pgtype.Interval - The extracted pgtype.Interval
+// @return bool - False if the value is NULL
+// @return error - The conversion error, if any
+func intervalFromAny(v any) (pgtype.Interval, bool, error) {
+	iv, err := revertPg[pgtype.Interval, time.Duration](v, SetIntervalFieldE)
+	if err != nil || !iv.Valid {
+		return pgtype.Interval{}, false, err
+	}
+	return iv, true, nil
+}
+
+// RevertPgRange reverts a pgtype.Range, a pointer to one or a driver.Valuer returning a range literal to a Range with plain Go bounds
+// Bounds are converted to G like RevertArrayField, e.g. time.Time for daterange or string for numrange
+// Range literals from a driver.Valuer are parsed as tstzrange for time.Time bounds, int8range for integers and numrange otherwise
+// It returns nil for NULL, and a Range with Empty set for the empty range
+// It's useful for converting a range column to Go bounds
+// @param r R - The range to convert, e.g. a pgtype.Range[pgtype.Date]
+// @return *Range[G] - The converted Range
+// @return error - A *ConvertError if the input is not a range or a bound cannot be converted to G
+func RevertPgRange[G any, R any](r R) (*Range[G], error) {
+	src, fromValuer, err := revertSource(r)
+	if err != nil {
+		return nil, newConvertError[Range[G]](r, err)
+	}
+	switch val := src.(type) {
+	case nil:
+		return nil, nil
+	case pgtype.Range[pgtype.Int4]:
+		return revertRange[G](val)
+	case pgtype.Range[pgtype.Int8]:
+		return revertRange[G](val)
+	case pgtype.Range[pgtype.Numeric]:
+		return revertRange[G](val)
+	case pgtype.Range[pgtype.Date]:
+		return revertRange[G](val)
+	case pgtype.Range[pgtype.Timestamp]:
+		return revertRange[G](val)
+	case pgtype.Range[pgtype.Timestamptz]:
+		return revertRange[G](val)
+	case string:
+		if fromValuer {
+			return revertRangeLiteral[G](val)
+		}
+	case []byte:
+		if fromValuer {
+			return revertRangeLiteral[G](string(val))
+		}
+	}
+	return nil, newConvertError[Range[G]](r, ErrUnsupportedType)
+}
+
+// revertRangeLiteral parses a range literal returned by a driver.Valuer and reverts it to a Range
+// The element type is picked from G: tstzrange for time.Time, int8range for integers and numrange otherwise
+// @param s string - The range literal
+// @return *Range[G] - The converted Range
+// @return error - A *ConvertError if the literal is invalid or a bound cannot be converted to G
+func revertRangeLiteral[G any](s string) (*Range[G], error) {
+	t := reflect.TypeOf((*G)(nil)).Elem()
+	for t.Kind() == reflect.Pointer && t != bigRatType {
+		t = t.Elem()
+	}
+	switch {
+	case t == timeType:
+		r, err := ParseRangeField[pgtype.Timestamptz](s)
+		if err != nil {
+			return nil, err
+		}
+		return revertRange[G](r)
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Uint64:
+		r, err := ParseRangeField[pgtype.Int8](s)
+		if err != nil {
+			return nil, err
+		}
+		return revertRange[G](r)
+	}
+	r, err := ParseRangeField[pgtype.Numeric](s)
+	if err != nil {
+		return nil, err
+	}
+	return revertRange[G](r)
+}
+
+// revertRange reverts a pgtype.Range to a Range with bounds converted to G
+// @param r pgtype.Range[T] - The range to convert
+// @return *Range[G] - The converted Range, nil for NULL
+// @return error - A *ConvertError if a bound cannot be converted to G
+func revertRange[G any, T RangeElement](r pgtype.Range[T]) (*Range[G], error) {
+	if !r.Valid {
+		return nil, nil
+	}
+	out := &Range[G]{}
+	if r.LowerType == pgtype.Empty || r.UpperType == pgtype.Empty {
+		out.Empty = true
+		return out, nil
+	}
+
+	bound := func(v T, bt pgtype.BoundType) (*RangeBound[G], error) {
+		if bt == pgtype.Unbounded {
+			return nil, nil
+		}
+		b := &RangeBound[G]{Inclusive: bt == pgtype.Inclusive}
+		if err := revertScalarInto(v, reflect.ValueOf(&b.Value).Elem()); err != nil {
+			return nil, err
+		}
+		return b, nil
+	}
+
+	var err error
+	if out.Lower, err = bound(r.Lower, r.LowerType); err != nil {
+		return nil, err
+	}
+	if out.Upper, err = bound(r.Upper, r.UpperType); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RevertPgTime reverts a pgtype.Time to a clock string formatted with a datecvx.TimeFormat
+// e.g. datecvx.Time_HHMM gives "08:30", NULL is returned as ""
+// 24:00:00 is formatted as midnight ("00:00")
+// It's useful for displaying opening hours and shift start times
+// @param v any - The value to convert to a string
+// @param format datecvx.TimeFormat - The format of the clock value
+// @return string - The formatted clock value
+func RevertPgTime(v any, format datecvx.TimeFormat) string {
+	s, _ := RevertPgTimeE(v, format)
+	return s
+}
+
+// RevertPgTimeE is the strict variant of RevertPgTime
+// It returns a *ConvertError when the value is not a pgtype.Time, a pointer to one or a driver.Valuer
+// @param v any - The value to convert to a string
+// @param format datecvx.TimeFormat - The format of the clock value
+// @return string - The formatted clock value, "" for NULL
+// @return error - The conversion error, if any
+func RevertPgTimeE(v any, format datecvx.TimeFormat) (string, error) {
+	t, ok, err := timeOfDayFromAny(v)
+	if !ok {
+		return "", err
+	}
+	clock := time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(t.Microseconds) * time.Microsecond)
+	return clock.Format(string(format)), nil
+}
+
+// RevertPgTimeDuration reverts a pgtype.Time to the duration since midnight
+// NULL is returned as 0
+// It's useful for comparing clock values or adding them to a date
+// @param v any - The value to convert to a time.Duration
+// @return time.Duration - The duration since midnight
+func RevertPgTimeDuration(v any) time.Duration {
+	d, _ := RevertPgTimeDurationE(v)
+	return d
+}
+
+// RevertPgTimeDurationE is the strict variant of RevertPgTimeDuration
+// @param v any - The value to convert to a time.Duration
+// @return time.Duration - The duration since midnight, 0 for NULL
+// @return error - The conversion error, if any
+func RevertPgTimeDurationE(v any) (time.Duration, error) {
+	t, _, err := timeOfDayFromAny(v)
+	return time.Duration(t.Microseconds) * time.Microsecond, err
+}
+
+// timeOfDayFromAny extracts a valid pgtype.Time
+// @param v any - The value to extract the pgtype.Time from
+// @return pgtype.Time - The extracted pgtype.Time
+// @return bool - False if the value is NULL
+// @return error - The conversion error, if any
+func timeOfDayFromAny(v any) (pgtype.Time, bool, error) {
+	t, err := revertPg[pgtype.Time, time.Duration](v, SetTimeFieldE)
+	if err != nil || !t.Valid {
+		return pgtype.Time{}, false, err
+	}
+	return t, true, nil
+}
+
+// RevertPgInet reverts an inet or cidr value to a netip.Prefix
+// It accepts netip.Prefix, netip.Addr, net.IPNet and strings, the way pgx may scan the column, NULL is returned as a zero netip.Prefix
+// IPv4-mapped IPv6 addresses are normalized to IPv4
+// It's useful for converting an inet or cidr column to a netip.Prefix
+// @param v any - The value to convert to a netip.Prefix
+// @return netip.Prefix - The converted netip.Prefix
+func RevertPgInet(v any) netip.Prefix {
+	p, _ := RevertPgInetE(v)
+	return p
+}
+
+// RevertPgInetE is the strict variant of RevertPgInet
+// It returns a *ConvertError when the value is not an address type, an address string or a driver.Valuer
+// @param v any - The value to convert to a netip.Prefix
+// @return netip.Prefix - The converted netip.Prefix, zero for NULL
+// @return error - The conversion error, if any
+func RevertPgInetE(v any) (netip.Prefix, error) {
+	src, _, err := revertSource(v)
+	if err == nil {
+		var p netip.Prefix
+		if p, err = prefixFromAny(src); err == nil {
+			return p, nil
+		}
+	}
+	return netip.Prefix{}, newConvertError[netip.Prefix](v, err)
+}
+
+// RevertPgInetAddr reverts an inet value to its netip.Addr, dropping the prefix length
+// NULL is returned as a zero netip.Addr
+// It's useful for converting a client IP column to a netip.Addr
+// @param v any - The value to convert to a netip.Addr
+// @return netip.Addr - The converted netip.Addr
+func RevertPgInetAddr(v any) netip.Addr {
+	return RevertPgInet(v).Addr()
+}
+
+// RevertPgInetAddrE is the strict variant of RevertPgInetAddr
+// @param v any - The value to convert to a netip.Addr
+// @return netip.Addr - The converted netip.Addr, zero for NULL
+// @return error - The conversion error, if any
+func RevertPgInetAddrE(v any) (netip.Addr, error) {
+	p, err := RevertPgInetE(v)
+	return p.Addr(), err
+}
+
+// RevertPgMacaddr reverts a macaddr or macaddr8 value to a lower-case colon separated string, e.g. "08:00:2b:01:02:03"
+// NULL is returned as ""
+// It's useful for converting a macaddr column to a string
+// @param v any - The value to convert to a string
+// @return string - The converted string
+func RevertPgMacaddr(v any) string {
+	s, _ := RevertPgMacaddrE(v)
+	return s
+}
+
+// RevertPgMacaddrE is the strict variant of RevertPgMacaddr
+// It returns a *ConvertError when the value is not a hardware address, a pointer to one or a driver.Valuer
+// @param v any - The value to convert to a string
+// @return string - The converted string, "" for NULL
+// @return error - The conversion error, if any
+func RevertPgMacaddrE(v any) (string, error) {
+	src, _, err := revertSource(v)
+	if err != nil {
+		return "", newConvertError[net.HardwareAddr](v, err)
+	}
+	mac, err := SetMacaddrFieldE(src)
+	if err != nil {
+		return "", err
+	}
+	return mac.String(), nil
+}
+
+// RevertPgBytea reverts a bytea value to a []byte
+// NULL is returned as nil, an empty bytea as a non-nil empty []byte
+// It's useful for converting a bytea column to a []byte
+// @param v any - The value to convert to a []byte
+// @return []byte - The converted []byte
+func RevertPgBytea(v any) []byte {
+	b, _ := RevertPgByteaE(v)
+	return b
+}
+
+// RevertPgByteaE is the strict variant of RevertPgBytea
+// It returns a *ConvertError when the value is not a []byte, a pointer to one or a driver.Valuer
+// @param v any - The value to convert to a []byte
+// @return []byte - The converted []byte, nil for NULL
+// @return error - The conversion error, if any
+func RevertPgByteaE(v any) ([]byte, error) {
+	src, fromValuer, err := revertSource(v)
+	if err != nil {
+		return nil, newConvertError[[]byte](v, err)
+	}
+	switch val := src.(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		return val, nil
+	}
+	if fromValuer {
+		return SetByteaFieldE(src)
+	}
+	return nil, newConvertError[[]byte](v, ErrUnsupportedType)
+}
+
+// RevertPgByteaString reverts a bytea value to an encoded string
+// ByteaHex gives PostgreSQL's "\x..." format, ByteaBase64 and ByteaBase64URL give padded base64, NULL is returned as ""
+// It's useful for returning binary data in JSON responses
+// @param v any - The value to convert to a string
+// @param enc ByteaEncoding - The encoding of the string
+// @return string - The encoded string
+func RevertPgByteaString(v any, enc ByteaEncoding) string {
+	s, _ := RevertPgByteaStringE(v, enc)
+	return s
+}
+
+// RevertPgByteaStringE is the strict variant of RevertPgByteaString
+// It returns a *ConvertError when the value is not a []byte, a pointer to one or a driver.Valuer
+// @param v any - The value to convert to a string
+// @param enc ByteaEncoding - The encoding of the string
+// @return string - The encoded string, "" for NULL
+// @return error - The conversion error, if any
+func RevertPgByteaStringE(v any, enc ByteaEncoding) (string, error) {
+	b, err := RevertPgByteaE(v)
+	if b == nil {
+		return "", err
+	}
+	switch enc {
+	case ByteaBase64:
+		return base64.StdEncoding.EncodeToString(b), nil
+	case ByteaBase64URL:
+		return base64.URLEncoding.EncodeToString(b), nil
+	default:
+		return `\x` + hex.EncodeToString(b), nil
+	}
+}
+
+// RevertPgHstore reverts a pgtype.Hstore to a map[string]string
+// Keys with a NULL value are left out, use RevertPgHstoreNullable to keep them
+// If the value is NULL or not a pgtype.Hstore, it returns nil
+// It's useful for converting an hstore column to an attribute map
+// @param v any - The value to convert to a map[string]string
+// @return map[string]string - The converted map
+func RevertPgHstore(v any) map[string]string {
+	m, _ := RevertPgHstoreE(v)
+	return m
+}
+
+// RevertPgHstoreE is the strict variant of RevertPgHstore
+// It returns a *ConvertError when the value is not a pgtype.Hstore, a pointer to one or a driver.Valuer
+// @param v any - The value to convert to a map[string]string
+// @return map[string]string - The converted map, nil for NULL
+// @return error - The conversion error, if any
+func RevertPgHstoreE(v any) (map[string]string, error) {
+	h, err := hstoreFromAny(v)
+	if h == nil {
+		return nil, err
+	}
+	out := make(map[string]string, len(h))
+	for k, s := range h {
+		if s != nil {
+			out[k] = *s
+		}
+	}
+	return out, nil
+}
+
+// RevertPgHstoreNullable reverts a pgtype.Hstore to a map[string]*string, NULL values are kept as nil
+// If the value is NULL or not a pgtype.Hstore, it returns nil
+// It's useful when a NULL value and a missing key mean different things
+// @param v any - The value to convert to a map[string]*string
+// @return map[string]*string - The converted map
+func RevertPgHstoreNullable(v any) map[string]*string {
+	h, _ := hstoreFromAny(v)
+	return h
+}
+
+// RevertPgHstoreNullableE is the strict variant of RevertPgHstoreNullable
+// @param v any - The value to convert to a map[string]*string
+// @return map[string]*string - The converted map, nil for NULL
+// @return error - The conversion error, if any
+func RevertPgHstoreNullableE(v any) (map[string]*string, error) {
+	return hstoreFromAny(v)
+}
+
+// hstoreFromAny extracts the pairs from a pgtype.Hstore or map[string]*string
+// @param v any - The value to extract the pairs from
+// @return map[string]*string - The extracted pairs, nil for NULL
+// @return error - The conversion error, if any
+func hstoreFromAny(v any) (map[string]*string, error) {
+	if m, ok := v.(map[string]*string); ok {
+		return m, nil
+	}
+	h, err := revertPg[pgtype.Hstore, map[string]string](v, SetHstoreFieldE)
+	if err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// RevertPgPoint reverts a pgtype.Point to a LatLng, reading X as longitude and Y as latitude
+// If the value is NULL or not a pgtype.Point, it returns the zero LatLng
+// It's useful for converting a point column to a location
+// @param v any - The value to convert to a LatLng
+// @return LatLng - The converted LatLng
+func RevertPgPoint(v any) LatLng {
+	p, _, _ := pointFromAny(v)
+	return p
+}
+
+// RevertPgPointE is the strict variant of RevertPgPoint
+// It returns a *ConvertError when the value is not a pgtype.Point, a pointer to one or a driver.Valuer
+// @param v any - The value to convert to a LatLng
+// @return LatLng - The converted LatLng, zero for NULL
+// @return error - The conversion error, if any
+func RevertPgPointE(v any) (LatLng, error) {
+	p, _, err := pointFromAny(v)
+	return p, err
+}
+
+// pointFromAny extracts the location of a valid pgtype.Point
+// @param v any - The value to extract the location from
+// @return LatLng - The extracted location
+// @return bool - False if the value is NULL
+// @return error - The conversion error, if any
+func pointFromAny(v any) (LatLng, bool, error) {
+	p, err := revertPg[pgtype.Point, LatLng](v, SetPointFieldE)
+	if err != nil || !p.Valid {
+		return LatLng{}, false, err
+	}
+	return LatLng{Lat: p.P.Y, Lng: p.P.X}, true, nil
+}
+
+// RevertPgBox reverts a pgtype.Box to a BoundingBox, reading X as longitude and Y as latitude
+// The corners are ordered so that Min is the lower left and Max the upper right corner
+// If the value is NULL or not a pgtype.Box, it returns the zero BoundingBox
+// It's useful for converting a box column to a bounding box
+// @param v any - The value to convert to a BoundingBox
+// @return BoundingBox - The converted BoundingBox
+func RevertPgBox(v any) BoundingBox {
+	b, _, _ := boxFromAny(v)
+	return b
+}
+
+// RevertPgBoxE is the strict variant of RevertPgBox
+// It returns a *ConvertError when the value is not a pgtype.Box, a pointer to one or a driver.Valuer
+// @param v any - The value to convert to a BoundingBox
+// @return BoundingBox - The converted BoundingBox, zero for NULL
+// @return error - The conversion error, if any
+func RevertPgBoxE(v any) (BoundingBox, error) {
+	b, _, err := boxFromAny(v)
+	return b, err
+}
+
+// boxFromAny extracts the bounding box of a valid pgtype.Box
+// @param v any - The value to extract the bounding box from
+// @return BoundingBox - The extracted bounding box
+// @return bool - False if the value is NULL
+// @return error - The conversion error, if any
+func boxFromAny(v any) (BoundingBox, bool, error) {
+	box, err := revertPg[pgtype.Box, BoundingBox](v, SetBoxFieldE)
+	if err != nil || !box.Valid {
+		return BoundingBox{}, false, err
 	}
-	return 0
+	a, b := box.P[0], box.P[1]
+	return BoundingBox{
+		Min: LatLng{Lat: math.Min(a.Y, b.Y), Lng: math.Min(a.X, b.X)},
+		Max: LatLng{Lat: math.Max(a.Y, b.Y), Lng: math.Max(a.X, b.X)},
+	}, true, nil
 }
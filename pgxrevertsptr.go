@@ -0,0 +1,547 @@
+package pgxhelpers
+
+import (
+	"math/big"
+	"net/netip"
+	"time"
+
+	"github.com/ChungNQ511/vnw-helpers/datecvx"
+)
+
+// The *Ptr reverts return nil for NULL and the *Or reverts return the caller's default,
+// so NULL stays different from "", 0, false and the zero time in API responses
+// Reverts that already return nil for NULL have no *Ptr variant, the hstore maps, RevertArrayField slices
+// and the *Range of RevertPgRange, ranges have no *Or variant either since a nil *Range is the NULL range
+
+// ptrIf returns a pointer to v, or nil if ok is false
+func ptrIf[T any](v T, ok bool) *T {
+	if !ok {
+		return nil
+	}
+	return &v
+}
+
+// orIf returns v, or def if ok is false
+func orIf[T any](v T, ok bool, def T) T {
+	if !ok {
+		return def
+	}
+	return v
+}
+
+// RevertPgTextPtr reverts a pgtype.Text to a *string, NULL is returned as nil
+// @param v any - The value to convert to a *string
+// @return *string - The converted *string
+func RevertPgTextPtr(v any) *string {
+	val, ok, _ := textFromAny(v)
+	return ptrIf(val, ok)
+}
+
+// RevertPgTextOr reverts a pgtype.Text to a string, NULL is returned as def
+// @param v any - The value to convert to a string
+// @param def string - The value returned for NULL
+// @return string - The converted string
+func RevertPgTextOr(v any, def string) string {
+	s, ok, _ := textFromAny(v)
+	return orIf(s, ok, def)
+}
+
+// RevertPgDatePtr reverts a pgtype.Date to a *time.Time, NULL is returned as nil
+// @param v any - The value to convert to a *time.Time
+// @return *time.Time - The converted *time.Time
+func RevertPgDatePtr(v any) *time.Time {
+	val, ok, _ := dateFromAny(v)
+	return ptrIf(val, ok)
+}
+
+// RevertPgDateOr reverts a pgtype.Date to a time.Time, NULL is returned as def
+// @param v any - The value to convert to a time.Time
+// @param def time.Time - The value returned for NULL
+// @return time.Time - The converted time.Time
+func RevertPgDateOr(v any, def time.Time) time.Time {
+	t, ok, _ := dateFromAny(v)
+	return orIf(t, ok, def)
+}
+
+// RevertPgTimestampPtr reverts a pgtype.Timestamp to a *time.Time, NULL is returned as nil
+// @param v any - The value to convert to a *time.Time
+// @return *time.Time - The converted *time.Time
+func RevertPgTimestampPtr(v any) *time.Time {
+	val, ok, _ := timestampFromAny(v)
+	return ptrIf(val, ok)
+}
+
+// RevertPgTimestampOr reverts a pgtype.Timestamp to a time.Time, NULL is returned as def
+// @param v any - The value to convert to a time.Time
+// @param def time.Time - The value returned for NULL
+// @return time.Time - The converted time.Time
+func RevertPgTimestampOr(v any, def time.Time) time.Time {
+	t, ok, _ := timestampFromAny(v)
+	return orIf(t, ok, def)
+}
+
+// RevertPgTimestamptzPtr reverts a pgtype.Timestamptz to a *time.Time, NULL is returned as nil
+// @param v any - The value to convert to a *time.Time
+// @return *time.Time - The converted *time.Time
+func RevertPgTimestamptzPtr(v any) *time.Time {
+	val, ok, _ := timestamptzFromAny(v)
+	return ptrIf(val, ok)
+}
+
+// RevertPgTimestamptzOr reverts a pgtype.Timestamptz to a time.Time, NULL is returned as def
+// @param v any - The value to convert to a time.Time
+// @param def time.Time - The value returned for NULL
+// @return time.Time - The converted time.Time
+func RevertPgTimestamptzOr(v any, def time.Time) time.Time {
+	t, ok, _ := timestamptzFromAny(v)
+	return orIf(t, ok, def)
+}
+
+// RevertPgBoolPtr reverts a pgtype.Bool to a *bool, NULL is returned as nil
+// @param v any - The value to convert to a *bool
+// @return *bool - The converted *bool
+func RevertPgBoolPtr(v any) *bool {
+	val, ok, _ := boolFromAny(v)
+	return ptrIf(val, ok)
+}
+
+// RevertPgBoolOr reverts a pgtype.Bool to a bool, NULL is returned as def
+// @param v any - The value to convert to a bool
+// @param def bool - The value returned for NULL
+// @return bool - The converted bool
+func RevertPgBoolOr(v any, def bool) bool {
+	b, ok, _ := boolFromAny(v)
+	return orIf(b, ok, def)
+}
+
+// RevertFloatFieldPtr reverts a pgtype.Float4 or pgtype.Float8 to a *float64, NULL is returned as nil
+// @param v T - The value to convert to a *float64
+// @return *float64 - The converted *float64
+func RevertFloatFieldPtr[T PgFloat](v T) *float64 {
+	val, ok, _ := pgFloatFromAny(v)
+	return ptrIf(val, ok)
+}
+
+// RevertFloatFieldOr reverts a pgtype.Float4 or pgtype.Float8 to a float64, NULL is returned as def
+// @param v T - The value to convert to a float64
+// @param def float64 - The value returned for NULL
+// @return float64 - The converted float64
+func RevertFloatFieldOr[T PgFloat](v T, def float64) float64 {
+	f, ok, _ := pgFloatFromAny(v)
+	return orIf(f, ok, def)
+}
+
+// RevertIntFieldPtr reverts a pgtype.Int2, pgtype.Int4 or pgtype.Int8 to an *int64, NULL is returned as nil
+// @param v T - The value to convert to an *int64
+// @return *int64 - The converted *int64
+func RevertIntFieldPtr[T PgInt](v T) *int64 {
+	val, ok, _ := pgIntFromAny(v)
+	return ptrIf(val, ok)
+}
+
+// RevertIntFieldOr reverts a pgtype.Int2, pgtype.Int4 or pgtype.Int8 to an int64, NULL is returned as def
+// @param v T - The value to convert to an int64
+// @param def int64 - The value returned for NULL
+// @return int64 - The converted int64
+func RevertIntFieldOr[T PgInt](v T, def int64) int64 {
+	n, ok, _ := pgIntFromAny(v)
+	return orIf(n, ok, def)
+}
+
+// RevertNumericFieldPtr reverts a pgtype.Numeric to an exact decimal *string, NULL is returned as nil
+// @param v any - The value to convert to a *string
+// @return *string - The converted *string
+func RevertNumericFieldPtr(v any) *string {
+	n, ok, _ := numericFromAny(v)
+	if !ok {
+		return nil
+	}
+	s := numericString(n)
+	return &s
+}
+
+// RevertNumericFieldOr reverts a pgtype.Numeric to an exact decimal string, NULL is returned as def
+// @param v any - The value to convert to a string
+// @param def string - The value returned for NULL
+// @return string - The converted string
+func RevertNumericFieldOr(v any, def string) string {
+	if s := RevertNumericFieldPtr(v); s != nil {
+		return *s
+	}
+	return def
+}
+
+// RevertNumericRatPtr reverts a pgtype.Numeric to an exact *big.Rat, NULL is returned as nil
+// It's the same as RevertNumericRat and is kept for symmetry with the other *Ptr reverts
+// @param v any - The value to convert to a *big.Rat
+// @return *big.Rat - The converted *big.Rat
+// @return error - ErrNonFinite if the value is NaN or infinity
+func RevertNumericRatPtr(v any) (*big.Rat, error) {
+	return RevertNumericRatOr(v, nil)
+}
+
+// RevertNumericRatOr reverts a pgtype.Numeric to an exact *big.Rat, NULL is returned as def
+// @param v any - The value to convert to a *big.Rat
+// @param def *big.Rat - The value returned for NULL
+// @return *big.Rat - The converted *big.Rat
+// @return error - ErrNonFinite if the value is NaN or infinity
+func RevertNumericRatOr(v any, def *big.Rat) (*big.Rat, error) {
+	n, ok, _ := numericFromAny(v)
+	if !ok {
+		return def, nil
+	}
+	return numericToRat(n)
+}
+
+// RevertNumericFloatPtr reverts a pgtype.Numeric to a *float64, NULL is returned as nil
+// @param v any - The value to convert to a *float64
+// @return *float64 - The converted *float64
+// @return bool - True if precision was lost
+func RevertNumericFloatPtr(v any) (*float64, bool) {
+	if _, ok, _ := numericFromAny(v); !ok {
+		return nil, false
+	}
+	f, lossy := RevertNumericFloat(v)
+	return &f, lossy
+}
+
+// RevertNumericFloatOr reverts a pgtype.Numeric to a float64, NULL is returned as def
+// @param v any - The value to convert to a float64
+// @param def float64 - The value returned for NULL
+// @return float64 - The converted float64
+// @return bool - True if precision was lost
+func RevertNumericFloatOr(v any, def float64) (float64, bool) {
+	if _, ok, _ := numericFromAny(v); !ok {
+		return def, false
+	}
+	return RevertNumericFloat(v)
+}
+
+// RevertNumericIntPtr reverts a pgtype.Numeric to an *int64, NULL is returned as nil
+// @param v any - The value to convert to an *int64
+// @return *int64 - The converted *int64
+// @return error - ErrFractional, ErrOverflow or ErrNonFinite like RevertNumericInt
+func RevertNumericIntPtr(v any) (*int64, error) {
+	n, ok, _ := numericFromAny(v)
+	if !ok {
+		return nil, nil
+	}
+	i, err := numericToInt64(n)
+	if err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
+
+// RevertNumericIntOr reverts a pgtype.Numeric to an int64, NULL is returned as def
+// @param v any - The value to convert to an int64
+// @param def int64 - The value returned for NULL
+// @return int64 - The converted int64
+// @return error - ErrFractional, ErrOverflow or ErrNonFinite like RevertNumericInt
+func RevertNumericIntOr(v any, def int64) (int64, error) {
+	n, ok, _ := numericFromAny(v)
+	if !ok {
+		return def, nil
+	}
+	return numericToInt64(n)
+}
+
+// RevertPgUUIDPtr reverts a pgtype.UUID to a canonical uuid *string, NULL is returned as nil
+// @param v any - The value to convert to a *string
+// @return *string - The converted *string
+func RevertPgUUIDPtr(v any) *string {
+	b, ok, _ := uuidFromAny(v)
+	if !ok {
+		return nil
+	}
+	s := formatUUID(b)
+	return &s
+}
+
+// RevertPgUUIDOr reverts a pgtype.UUID to a canonical uuid string, NULL is returned as def
+// @param v any - The value to convert to a string
+// @param def string - The value returned for NULL
+// @return string - The converted string
+func RevertPgUUIDOr(v any, def string) string {
+	if s := RevertPgUUIDPtr(v); s != nil {
+		return *s
+	}
+	return def
+}
+
+// RevertPgUUIDBytesPtr reverts a pgtype.UUID to a *[16]byte, NULL is returned as nil
+// @param v any - The value to convert to a *[16]byte
+// @return *[16]byte - The converted *[16]byte
+func RevertPgUUIDBytesPtr(v any) *[16]byte {
+	val, ok, _ := uuidFromAny(v)
+	return ptrIf(val, ok)
+}
+
+// RevertPgUUIDBytesOr reverts a pgtype.UUID to a [16]byte, NULL is returned as def
+// @param v any - The value to convert to a [16]byte
+// @param def [16]byte - The value returned for NULL
+// @return [16]byte - The converted [16]byte
+func RevertPgUUIDBytesOr(v any, def [16]byte) [16]byte {
+	val, ok, _ := uuidFromAny(v)
+	return orIf(val, ok, def)
+}
+
+// RevertPgIntervalPtr reverts a pgtype.Interval to a *time.Duration, NULL is returned as nil
+// @param v any - The value to convert to a *time.Duration
+// @return *time.Duration - The converted *time.Duration
+// @return error - ErrAmbiguousInterval or ErrOverflow like RevertPgInterval
+func RevertPgIntervalPtr(v any) (*time.Duration, error) {
+	if _, ok, _ := intervalFromAny(v); !ok {
+		return nil, nil
+	}
+	d, err := RevertPgInterval(v)
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// RevertPgIntervalOr reverts a pgtype.Interval to a time.Duration, NULL is returned as def
+// @param v any - The value to convert to a time.Duration
+// @param def time.Duration - The value returned for NULL
+// @return time.Duration - The converted time.Duration
+// @return error - ErrAmbiguousInterval or ErrOverflow like RevertPgInterval
+func RevertPgIntervalOr(v any, def time.Duration) (time.Duration, error) {
+	if _, ok, _ := intervalFromAny(v); !ok {
+		return def, nil
+	}
+	return RevertPgInterval(v)
+}
+
+// RevertPgIntervalPartsPtr reverts a pgtype.Interval to *IntervalParts, NULL is returned as nil
+// @param v any - The value to convert to *IntervalParts
+// @return *IntervalParts - The converted *IntervalParts
+func RevertPgIntervalPartsPtr(v any) *IntervalParts {
+	if _, ok, _ := intervalFromAny(v); !ok {
+		return nil
+	}
+	parts := RevertPgIntervalParts(v)
+	return &parts
+}
+
+// RevertPgIntervalPartsOr reverts a pgtype.Interval to IntervalParts, NULL is returned as def
+// @param v any - The value to convert to IntervalParts
+// @param def IntervalParts - The value returned for NULL
+// @return IntervalParts - The converted IntervalParts
+func RevertPgIntervalPartsOr(v any, def IntervalParts) IntervalParts {
+	if parts := RevertPgIntervalPartsPtr(v); parts != nil {
+		return *parts
+	}
+	return def
+}
+
+// RevertPgTimePtr reverts a pgtype.Time to a clock *string formatted with a datecvx.TimeFormat, NULL is returned as nil
+// @param v any - The value to convert to a *string
+// @param format datecvx.TimeFormat - The format of the clock value
+// @return *string - The formatted clock value
+func RevertPgTimePtr(v any, format datecvx.TimeFormat) *string {
+	if _, ok, _ := timeOfDayFromAny(v); !ok {
+		return nil
+	}
+	s := RevertPgTime(v, format)
+	return &s
+}
+
+// RevertPgTimeOr reverts a pgtype.Time to a clock string formatted with a datecvx.TimeFormat, NULL is returned as def
+// @param v any - The value to convert to a string
+// @param format datecvx.TimeFormat - The format of the clock value
+// @param def string - The value returned for NULL
+// @return string - The formatted clock value
+func RevertPgTimeOr(v any, format datecvx.TimeFormat, def string) string {
+	if s := RevertPgTimePtr(v, format); s != nil {
+		return *s
+	}
+	return def
+}
+
+// RevertPgTimeDurationPtr reverts a pgtype.Time to a *time.Duration since midnight, NULL is returned as nil
+// @param v any - The value to convert to a *time.Duration
+// @return *time.Duration - The duration since midnight
+func RevertPgTimeDurationPtr(v any) *time.Duration {
+	if _, ok, _ := timeOfDayFromAny(v); !ok {
+		return nil
+	}
+	d := RevertPgTimeDuration(v)
+	return &d
+}
+
+// RevertPgTimeDurationOr reverts a pgtype.Time to the duration since midnight, NULL is returned as def
+// @param v any - The value to convert to a time.Duration
+// @param def time.Duration - The value returned for NULL
+// @return time.Duration - The duration since midnight
+func RevertPgTimeDurationOr(v any, def time.Duration) time.Duration {
+	if d := RevertPgTimeDurationPtr(v); d != nil {
+		return *d
+	}
+	return def
+}
+
+// RevertPgInetPtr reverts an inet or cidr value to a *netip.Prefix, NULL is returned as nil
+// @param v any - The value to convert to a *netip.Prefix
+// @return *netip.Prefix - The converted *netip.Prefix
+func RevertPgInetPtr(v any) *netip.Prefix {
+	p := RevertPgInet(v)
+	return ptrIf(p, p.IsValid())
+}
+
+// RevertPgInetAddrPtr reverts an inet value to a *netip.Addr, NULL is returned as nil
+// @param v any - The value to convert to a *netip.Addr
+// @return *netip.Addr - The converted *netip.Addr
+func RevertPgInetAddrPtr(v any) *netip.Addr {
+	a := RevertPgInetAddr(v)
+	return ptrIf(a, a.IsValid())
+}
+
+// RevertPgInetOr reverts an inet or cidr value to a netip.Prefix, NULL is returned as def
+// @param v any - The value to convert to a netip.Prefix
+// @param def netip.Prefix - The value returned for NULL
+// @return netip.Prefix - The converted netip.Prefix
+func RevertPgInetOr(v any, def netip.Prefix) netip.Prefix {
+	p := RevertPgInet(v)
+	return orIf(p, p.IsValid(), def)
+}
+
+// RevertPgInetAddrOr reverts an inet value to a netip.Addr, NULL is returned as def
+// @param v any - The value to convert to a netip.Addr
+// @param def netip.Addr - The value returned for NULL
+// @return netip.Addr - The converted netip.Addr
+func RevertPgInetAddrOr(v any, def netip.Addr) netip.Addr {
+	a := RevertPgInetAddr(v)
+	return orIf(a, a.IsValid(), def)
+}
+
+// RevertPgMacaddrPtr reverts a macaddr or macaddr8 value to a *string, NULL is returned as nil
+// @param v any - The value to convert to a *string
+// @return *string - The converted *string
+func RevertPgMacaddrPtr(v any) *string {
+	s := RevertPgMacaddr(v)
+	return ptrIf(s, s != "")
+}
+
+// RevertPgMacaddrOr reverts a macaddr or macaddr8 value to a string, NULL is returned as def
+// @param v any - The value to convert to a string
+// @param def string - The value returned for NULL
+// @return string - The converted string
+func RevertPgMacaddrOr(v any, def string) string {
+	s := RevertPgMacaddr(v)
+	return orIf(s, s != "", def)
+}
+
+// RevertPgByteaStringPtr reverts a bytea value to an encoded *string, NULL is returned as nil
+// @param v any - The value to convert to a *string
+// @param enc ByteaEncoding - The encoding of the string
+// @return *string - The encoded *string
+func RevertPgByteaStringPtr(v any, enc ByteaEncoding) *string {
+	if RevertPgBytea(v) == nil {
+		return nil
+	}
+	s := RevertPgByteaString(v, enc)
+	return &s
+}
+
+// RevertPgByteaStringOr reverts a bytea value to an encoded string, NULL is returned as def
+// @param v any - The value to convert to a string
+// @param enc ByteaEncoding - The encoding of the string
+// @param def string - The value returned for NULL
+// @return string - The encoded string
+func RevertPgByteaStringOr(v any, enc ByteaEncoding, def string) string {
+	if s := RevertPgByteaStringPtr(v, enc); s != nil {
+		return *s
+	}
+	return def
+}
+
+// RevertPgPointPtr reverts a pgtype.Point to a *LatLng, NULL is returned as nil
+// @param v any - The value to convert to a *LatLng
+// @return *LatLng - The converted *LatLng
+func RevertPgPointPtr(v any) *LatLng {
+	val, ok, _ := pointFromAny(v)
+	return ptrIf(val, ok)
+}
+
+// RevertPgPointOr reverts a pgtype.Point to a LatLng, NULL is returned as def
+// @param v any - The value to convert to a LatLng
+// @param def LatLng - The value returned for NULL
+// @return LatLng - The converted LatLng
+func RevertPgPointOr(v any, def LatLng) LatLng {
+	p, ok, _ := pointFromAny(v)
+	return orIf(p, ok, def)
+}
+
+// RevertPgBoxPtr reverts a pgtype.Box to a *BoundingBox, NULL is returned as nil
+// @param v any - The value to convert to a *BoundingBox
+// @return *BoundingBox - The converted *BoundingBox
+func RevertPgBoxPtr(v any) *BoundingBox {
+	val, ok, _ := boxFromAny(v)
+	return ptrIf(val, ok)
+}
+
+// RevertPgBoxOr reverts a pgtype.Box to a BoundingBox, NULL is returned as def
+// @param v any - The value to convert to a BoundingBox
+// @param def BoundingBox - The value returned for NULL
+// @return BoundingBox - The converted BoundingBox
+func RevertPgBoxOr(v any, def BoundingBox) BoundingBox {
+	val, ok, _ := boxFromAny(v)
+	return orIf(val, ok, def)
+}
+
+// RevertPgHstoreOr reverts a pgtype.Hstore to a map[string]string, NULL is returned as def
+// Keys with a NULL value are left out like RevertPgHstore
+// @param v any - The value to convert to a map[string]string
+// @param def map[string]string - The value returned for NULL
+// @return map[string]string - The converted map
+func RevertPgHstoreOr(v any, def map[string]string) map[string]string {
+	m := RevertPgHstore(v)
+	return orIf(m, m != nil, def)
+}
+
+// RevertPgHstoreNullableOr reverts a pgtype.Hstore to a map[string]*string, NULL is returned as def
+// @param v any - The value to convert to a map[string]*string
+// @param def map[string]*string - The value returned for NULL
+// @return map[string]*string - The converted map
+func RevertPgHstoreNullableOr(v any, def map[string]*string) map[string]*string {
+	m := RevertPgHstoreNullable(v)
+	return orIf(m, m != nil, def)
+}
+
+// RevertArrayFieldOr reverts a pgtype.Array or pgtype.FlatArray to a []E, NULL is returned as def
+// An empty array is returned as an empty slice, not as def
+// @param v any - The pgtype.Array or pgtype.FlatArray to convert
+// @param def []E - The value returned for NULL
+// @return []E - The converted slice
+func RevertArrayFieldOr[E any](v any, def []E) []E {
+	out := RevertArrayField[E](v)
+	return orIf(out, out != nil, def)
+}
+
+// RevertJSONFieldPtr unmarshals a json or jsonb value into a *T
+// NULL and the JSON literal null are returned as nil
+// @param v any - The value to unmarshal
+// @return *T - The unmarshaled value
+// @return error - A *ConvertError if the value is not valid JSON for T
+func RevertJSONFieldPtr[T any](v any) (*T, error) {
+	out, err := RevertJSONField[*T](v)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RevertJSONFieldOr unmarshals a json or jsonb value into T
+// NULL and the JSON literal null are returned as def
+// @param v any - The value to unmarshal
+// @param def T - The value returned for NULL
+// @return T - The unmarshaled value
+// @return error - A *ConvertError if the value is not valid JSON for T
+func RevertJSONFieldOr[T any](v any, def T) (T, error) {
+	out, err := RevertJSONFieldPtr[T](v)
+	if err != nil || out == nil {
+		return def, err
+	}
+	return *out, nil
+}
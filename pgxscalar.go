@@ -0,0 +1,202 @@
+package pgxhelpers
+
+import (
+	"math/big"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// PgScalar lists the pgtype structs that the generic array helpers convert elements to and from
+type PgScalar interface {
+	pgtype.Text | pgtype.Int2 | pgtype.Int4 | pgtype.Int8 | pgtype.Float4 | pgtype.Float8 | pgtype.Bool |
+		pgtype.Date | pgtype.Timestamp | pgtype.Timestamptz | pgtype.UUID | pgtype.Numeric
+}
+
+// scalarValue extracts the Go value of a pgtype scalar
+// Integers are returned as int64, floats as float64, dates and timestamps as time.Time, uuids as [16]byte
+// and numerics as pgtype.Numeric
+// @param src any - The pgtype scalar or a pointer to it
+// @return any - The Go value
+// @return bool - False if the value is NULL
+// @return bool - False if the value is not a supported pgtype scalar
+func scalarValue(src any) (any, bool, bool) {
+	switch val := src.(type) {
+	case nil:
+		return nil, false, true
+	case pgtype.Text:
+		return val.String, val.Valid, true
+	case pgtype.Int2:
+		return int64(val.Int16), val.Valid, true
+	case pgtype.Int4:
+		return int64(val.Int32), val.Valid, true
+	case pgtype.Int8:
+		return val.Int64, val.Valid, true
+	case pgtype.Float4:
+		return float64(val.Float32), val.Valid, true
+	case pgtype.Float8:
+		return val.Float64, val.Valid, true
+	case pgtype.Bool:
+		return val.Bool, val.Valid, true
+	case pgtype.Date:
+		return val.Time, val.Valid, true
+	case pgtype.Timestamp:
+		return val.Time, val.Valid, true
+	case pgtype.Timestamptz:
+		return val.Time, val.Valid, true
+	case pgtype.UUID:
+		return val.Bytes, val.Valid, true
+	case pgtype.Numeric:
+		return val, val.Valid, true
+	}
+
+	rv := reflect.ValueOf(src)
+	if rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil, false, true
+		}
+		return scalarValue(rv.Elem().Interface())
+	}
+	return nil, false, false
+}
+
+var (
+	timeType   = reflect.TypeOf(time.Time{})
+	bigRatType = reflect.TypeOf((*big.Rat)(nil))
+)
+
+// revertScalarInto converts a pgtype scalar into dst, which can be a Go type or a pointer to one
+// NULL sets pointers to nil and other types to their zero value
+// @param src any - The pgtype scalar
+// @param dst reflect.Value - The settable destination
+// @return error - A *ConvertError if the value cannot be stored in dst
+func revertScalarInto(src any, dst reflect.Value) error {
+	if reflect.TypeOf(src) == dst.Type() {
+		dst.Set(reflect.ValueOf(src))
+		return nil
+	}
+
+	val, valid, ok := scalarValue(src)
+	if !ok {
+		return revertError(src, dst, ErrUnsupportedType)
+	}
+	if !valid {
+		dst.SetZero()
+		return nil
+	}
+
+	if dst.Type() == bigRatType {
+		n, isNumeric := val.(pgtype.Numeric)
+		if !isNumeric {
+			return revertError(src, dst, ErrUnsupportedType)
+		}
+		r, err := numericToRat(n)
+		if err != nil {
+			return revertError(src, dst, err)
+		}
+		dst.Set(reflect.ValueOf(r))
+		return nil
+	}
+	if dst.Kind() == reflect.Pointer {
+		elem := reflect.New(dst.Type().Elem())
+		if err := revertScalarInto(src, elem.Elem()); err != nil {
+			return err
+		}
+		dst.Set(elem)
+		return nil
+	}
+
+	var err error
+	switch dst.Kind() {
+	case reflect.String:
+		switch v := val.(type) {
+		case string:
+			dst.SetString(v)
+		case [16]byte:
+			dst.SetString(formatUUID(v))
+		case pgtype.Numeric:
+			dst.SetString(numericString(v))
+		case int64:
+			dst.SetString(strconv.FormatInt(v, 10))
+		case float64:
+			dst.SetString(strconv.FormatFloat(v, 'f', -1, 64))
+		case bool:
+			dst.SetString(strconv.FormatBool(v))
+		case time.Time:
+			dst.SetString(v.Format(time.RFC3339Nano))
+		default:
+			err = ErrUnsupportedType
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var n int64
+		switch v := val.(type) {
+		case int64:
+			n = v
+		case pgtype.Numeric:
+			n, err = numericToInt64(v)
+		default:
+			err = ErrUnsupportedType
+		}
+		if err == nil && dst.OverflowInt(n) {
+			err = ErrOverflow
+		}
+		if err == nil {
+			dst.SetInt(n)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var n int64
+		switch v := val.(type) {
+		case int64:
+			n = v
+		case pgtype.Numeric:
+			n, err = numericToInt64(v)
+		default:
+			err = ErrUnsupportedType
+		}
+		if err == nil && (n < 0 || dst.OverflowUint(uint64(n))) {
+			err = ErrOverflow
+		}
+		if err == nil {
+			dst.SetUint(uint64(n))
+		}
+	case reflect.Float32, reflect.Float64:
+		switch v := val.(type) {
+		case float64:
+			dst.SetFloat(v)
+		case int64:
+			dst.SetFloat(float64(v))
+		case pgtype.Numeric:
+			f, _ := RevertNumericFloat(v)
+			dst.SetFloat(f)
+		default:
+			err = ErrUnsupportedType
+		}
+	case reflect.Bool:
+		if b, isBool := val.(bool); isBool {
+			dst.SetBool(b)
+		} else {
+			err = ErrUnsupportedType
+		}
+	default:
+		if rv := reflect.ValueOf(val); rv.Type().ConvertibleTo(dst.Type()) && (dst.Type() == timeType || dst.Kind() == reflect.Array) {
+			dst.Set(rv.Convert(dst.Type()))
+		} else {
+			err = ErrUnsupportedType
+		}
+	}
+	if err != nil {
+		return revertError(src, dst, err)
+	}
+	return nil
+}
+
+// revertError creates a *ConvertError for a value that cannot be reverted into dst
+// @param src any - The value that was reverted
+// @param dst reflect.Value - The destination
+// @param err error - The cause of the failure
+// @return error - The *ConvertError
+func revertError(src any, dst reflect.Value, err error) error {
+	return &ConvertError{Input: src, Target: dst.Type().String(), Err: err}
+}
@@ -0,0 +1,273 @@
+package pgxhelpers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/netip"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ScanOption configures how ScanStruct maps columns to struct fields
+type ScanOption func(*scanConfig)
+
+// scanConfig holds the options of ScanStruct
+type scanConfig struct {
+	strict bool
+}
+
+// WithStrictScan makes ScanStruct also fail when a struct field has no column in the result
+// and when a NULL is scanned into a field that cannot hold it (not a pointer, map, slice, Nullable or pgtype)
+// @return ScanOption - The option
+func WithStrictScan() ScanOption {
+	return func(c *scanConfig) {
+		c.strict = true
+	}
+}
+
+// scanIntermediates maps column type OIDs to the pgtype values columns are scanned into before reverting
+var scanIntermediates = map[uint32]func() any{
+	pgtype.TextOID:        func() any { return new(pgtype.Text) },
+	pgtype.VarcharOID:     func() any { return new(pgtype.Text) },
+	pgtype.BPCharOID:      func() any { return new(pgtype.Text) },
+	pgtype.NameOID:        func() any { return new(pgtype.Text) },
+	pgtype.Int2OID:        func() any { return new(pgtype.Int2) },
+	pgtype.Int4OID:        func() any { return new(pgtype.Int4) },
+	pgtype.Int8OID:        func() any { return new(pgtype.Int8) },
+	pgtype.Float4OID:      func() any { return new(pgtype.Float4) },
+	pgtype.Float8OID:      func() any { return new(pgtype.Float8) },
+	pgtype.NumericOID:     func() any { return new(pgtype.Numeric) },
+	pgtype.BoolOID:        func() any { return new(pgtype.Bool) },
+	pgtype.DateOID:        func() any { return new(pgtype.Date) },
+	pgtype.TimestampOID:   func() any { return new(pgtype.Timestamp) },
+	pgtype.TimestamptzOID: func() any { return new(pgtype.Timestamptz) },
+	pgtype.TimeOID:        func() any { return new(pgtype.Time) },
+	pgtype.IntervalOID:    func() any { return new(pgtype.Interval) },
+	pgtype.UUIDOID:        func() any { return new(pgtype.UUID) },
+	pgtype.JSONOID:        func() any { return new(json.RawMessage) },
+	pgtype.JSONBOID:       func() any { return new(json.RawMessage) },
+	pgtype.ByteaOID:       func() any { return new([]byte) },
+	pgtype.InetOID:        func() any { return new(netip.Prefix) },
+	pgtype.CIDROID:        func() any { return new(netip.Prefix) },
+	pgtype.MacaddrOID:     func() any { return new(net.HardwareAddr) },
+	pgtype.PointOID:       func() any { return new(pgtype.Point) },
+	pgtype.BoxOID:         func() any { return new(pgtype.Box) },
+}
+
+var sqlScannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+
+// scanColumn is a result column mapped to a struct field
+type scanColumn struct {
+	name         string
+	oid          uint32
+	field        columnField
+	intermediate func() any
+}
+
+// scanPlan maps the columns of a result to the fields of a struct type
+type scanPlan struct {
+	columns []scanColumn
+	strict  bool
+}
+
+// newScanPlan matches the columns of a row to the fields of a struct type by db tag or snake_case name
+// @param row pgx.CollectableRow - The row, only its field descriptions are used
+// @param t reflect.Type - The struct type
+// @param cfg scanConfig - The scan options
+// @return *scanPlan - The plan
+// @return error - An error for columns without a field, or fields without a column in strict mode
+func newScanPlan(row pgx.CollectableRow, t reflect.Type, cfg scanConfig) (*scanPlan, error) {
+	fields := columnFields(t)
+	byName := make(map[string]columnField, len(fields))
+	for _, f := range fields {
+		byName[strings.ToLower(f.tag.name)] = f
+	}
+
+	plan := &scanPlan{strict: cfg.strict}
+	used := make(map[string]bool)
+	for _, fd := range row.FieldDescriptions() {
+		key := strings.ToLower(fd.Name)
+		f, ok := byName[key]
+		if !ok {
+			return nil, fmt.Errorf("pgxhelpers: column %q has no field in %s", fd.Name, t)
+		}
+		used[key] = true
+
+		col := scanColumn{name: fd.Name, oid: fd.DataTypeOID, field: f}
+		ft := t.FieldByIndex(f.index).Type
+		// pgtype fields, interfaces and scanners such as Nullable are scanned by pgx itself
+		if ft.PkgPath() != pgtypePkgPath && ft.Kind() != reflect.Interface && !reflect.PointerTo(ft).Implements(sqlScannerType) {
+			col.intermediate = scanIntermediates[fd.DataTypeOID]
+		}
+		plan.columns = append(plan.columns, col)
+	}
+
+	if cfg.strict {
+		for _, f := range fields {
+			if !used[strings.ToLower(f.tag.name)] {
+				return nil, fmt.Errorf("pgxhelpers: field for column %q of %s is not in the result", f.tag.name, t)
+			}
+		}
+	}
+	return plan, nil
+}
+
+// matches reports whether the plan was built for the given result columns
+// @param fds []pgconn.FieldDescription - The columns of the result
+// @return bool - True if the names and types of the columns are the same
+func (p *scanPlan) matches(fds []pgconn.FieldDescription) bool {
+	if len(fds) != len(p.columns) {
+		return false
+	}
+	for i, fd := range fds {
+		if fd.Name != p.columns[i].name || fd.DataTypeOID != p.columns[i].oid {
+			return false
+		}
+	}
+	return true
+}
+
+// scan scans the current row into the struct dst
+// @param row pgx.CollectableRow - The row to scan
+// @param dst reflect.Value - The settable struct value
+// @return error - The scan or conversion error, if any
+func (p *scanPlan) scan(row pgx.CollectableRow, dst reflect.Value) error {
+	targets := make([]any, len(p.columns))
+	for i, col := range p.columns {
+		if col.intermediate != nil {
+			targets[i] = col.intermediate()
+		} else {
+			targets[i] = settableColumnValue(dst, col.field).Addr().Interface()
+		}
+	}
+	if err := row.Scan(targets...); err != nil {
+		return err
+	}
+
+	for i, col := range p.columns {
+		if col.intermediate == nil {
+			continue
+		}
+		src := reflect.ValueOf(targets[i]).Elem().Interface()
+		fv := settableColumnValue(dst, col.field)
+		if p.strict && isNullValue(src) && !canHoldNull(fv.Type()) {
+			return fmt.Errorf("pgxhelpers: column %q: NULL cannot be stored in %s", col.name, fv.Type())
+		}
+		if err := revertInto(src, fv); err != nil {
+			return fmt.Errorf("pgxhelpers: column %q: %w", col.name, err)
+		}
+	}
+	return nil
+}
+
+// isNullValue reports whether a scanned intermediate value is NULL
+func isNullValue(src any) bool {
+	if valid, ok := nonScalarValid(src); ok {
+		return !valid
+	}
+	_, valid, _ := scalarValue(src)
+	return !valid
+}
+
+// canHoldNull reports whether a field type has a value for NULL
+func canHoldNull(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface:
+		return true
+	}
+	return false
+}
+
+// ScanStruct scans the current row into a struct, mapping columns to fields by db tag or snake_case field name
+// Columns are scanned into pgtype values and reverted to plain Go fields like Revert, e.g. int4 to int, text to string,
+// nullable columns to pointers; pgtype fields and sql.Scanner fields such as Nullable are scanned directly
+// It returns an error for columns without a field and values that cannot be stored in their field
+// It's useful for mapping query results to domain structs without pgtype variables
+// @param row pgx.CollectableRow - The row to scan, e.g. pgx.Rows after Next
+// @param dst any - A pointer to the struct
+// @param opts ...ScanOption - WithStrictScan to also require every field and reject NULL in non-nullable fields
+// @return error - The scan or conversion error, if any
+func ScanStruct(row pgx.CollectableRow, dst any, opts ...ScanOption) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("pgxhelpers: ScanStruct needs a non-nil pointer to a struct, got %T", dst)
+	}
+
+	var cfg scanConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	plan, err := newScanPlan(row, rv.Elem().Type(), cfg)
+	if err != nil {
+		return err
+	}
+	return plan.scan(row, rv.Elem())
+}
+
+// RowToStruct returns a pgx.RowToFunc that scans rows into T like ScanStruct
+// The column mapping is built from the first row and reused while the columns stay the same,
+// so the function can be kept and shared between queries and goroutines
+// It's useful with pgx.CollectRows and pgx.CollectOneRow
+// @param opts ...ScanOption - The scan options
+// @return pgx.RowToFunc[T] - The row function
+func RowToStruct[T any](opts ...ScanOption) pgx.RowToFunc[T] {
+	var cfg scanConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	var (
+		mu     sync.Mutex
+		cached *scanPlan
+	)
+	return func(row pgx.CollectableRow) (T, error) {
+		var out T
+		rv := reflect.ValueOf(&out).Elem()
+		if rv.Kind() != reflect.Struct {
+			return out, fmt.Errorf("pgxhelpers: RowToStruct needs a struct type, got %T", out)
+		}
+
+		mu.Lock()
+		plan := cached
+		if plan == nil || !plan.matches(row.FieldDescriptions()) {
+			var err error
+			if plan, err = newScanPlan(row, rv.Type(), cfg); err != nil {
+				mu.Unlock()
+				return out, err
+			}
+			cached = plan
+		}
+		mu.Unlock()
+
+		if err := plan.scan(row, rv); err != nil {
+			var zero T
+			return zero, err
+		}
+		return out, nil
+	}
+}
+
+// ScanRows scans every row into a slice of T and closes rows
+// @param rows pgx.Rows - The query result
+// @param opts ...ScanOption - The scan options
+// @return []T - The scanned structs
+// @return error - The query, scan or conversion error, if any
+func ScanRows[T any](rows pgx.Rows, opts ...ScanOption) ([]T, error) {
+	return pgx.CollectRows(rows, RowToStruct[T](opts...))
+}
+
+// ScanOne scans the first row into T and closes rows, it returns pgx.ErrNoRows if there is no row
+// @param rows pgx.Rows - The query result
+// @param opts ...ScanOption - The scan options
+// @return T - The scanned struct
+// @return error - The query, scan or conversion error, if any
+func ScanOne[T any](rows pgx.Rows, opts ...ScanOption) (T, error) {
+	return pgx.CollectOneRow(rows, RowToStruct[T](opts...))
+}
@@ -0,0 +1,142 @@
+package pgxhelpers
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ChungNQ511/vnw-helpers/funcvx"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// SetTimeField sets a clock string, the clock part of a time.Time or a duration since midnight to a pgtype.Time
+// Strings can be "08:30", "8:30:15", "08:30:15.5", "8h30", "8:30 SA", "2:15 CH", "8:30 AM" or "24:00"
+// (SA/sáng is AM, CH/chiều/tối is PM)
+// If the value is not supported, cannot be parsed or is outside 00:00-24:00, it returns a pgtype.Time with 0 and false
+// It's useful for converting opening hours and shift start times to a pgtype.Time
+// @param v any - The value to convert to a pgtype.Time
+// @return pgtype.Time - The converted pgtype.Time
+func SetTimeField(v any) pgtype.Time {
+	out, _ := SetTimeFieldE(v)
+	return out
+}
+
+// SetTimeFieldE is the strict variant of SetTimeField
+// It returns a *ConvertError instead of an invalid pgtype.Time when the value is not supported, cannot be parsed
+// or is outside 00:00-24:00 (ErrOverflow)
+// A nil value, nil pointer or null string is not an error, it returns a pgtype.Time with false
+// @param v any - The value to convert to a pgtype.Time
+// @return pgtype.Time - The converted pgtype.Time
+// @return error - The conversion error, if any
+func SetTimeFieldE(v any) (pgtype.Time, error) {
+	switch val := v.(type) {
+	case nil:
+	case time.Time:
+		return pgtype.Time{Microseconds: clockMicros(val), Valid: true}, nil
+	case *time.Time:
+		if val != nil {
+			return pgtype.Time{Microseconds: clockMicros(*val), Valid: true}, nil
+		}
+	case time.Duration:
+		if val < 0 || val > 24*time.Hour {
+			return pgtype.Time{}, newConvertError[pgtype.Time](v, fmt.Errorf("%w: duration since midnight must be between 0 and 24h", ErrOverflow))
+		}
+		return pgtype.Time{Microseconds: val.Microseconds(), Valid: true}, nil
+	case *time.Duration:
+		if val != nil {
+			return SetTimeFieldE(*val)
+		}
+	case pgtype.Time:
+		return val, nil
+	case string:
+		return stringToPgTime(val)
+	case *string:
+		if val != nil {
+			return stringToPgTime(*val)
+		}
+	default:
+		return pgtype.Time{}, newConvertError[pgtype.Time](v, ErrUnsupportedType)
+	}
+	return pgtype.Time{}, nil
+}
+
+// clockMicros returns the microseconds since midnight of the clock part of a time.Time
+// @param t time.Time - The time
+// @return int64 - The microseconds since midnight
+func clockMicros(t time.Time) int64 {
+	h, m, s := t.Clock()
+	return (int64(h)*3600+int64(m)*60+int64(s))*microsPerSecond + int64(t.Nanosecond())/1000
+}
+
+// meridiemSuffixes maps AM/PM markers to true for PM, Vietnamese SA (sáng) is AM and CH (chiều) is PM
+var meridiemSuffixes = []struct {
+	suffix string
+	pm     bool
+}{
+	{"sáng", false}, {"chiều", true}, {"tối", true},
+	{"sa", false}, {"ch", true}, {"am", false}, {"pm", true},
+}
+
+// stringToPgTime converts a clock string to a pgtype.Time
+// @param s string - The value to convert to a pgtype.Time
+// @return pgtype.Time - The converted pgtype.Time
+// @return error - A *ConvertError if the string cannot be parsed
+func stringToPgTime(s string) (pgtype.Time, error) {
+	if !funcvx.NotNull(s) {
+		return pgtype.Time{}, nil
+	}
+
+	input := strings.ToLower(strings.TrimSpace(s))
+	meridiem, pm := false, false
+	for _, m := range meridiemSuffixes {
+		if strings.HasSuffix(input, m.suffix) {
+			input = strings.TrimSpace(strings.TrimSuffix(input, m.suffix))
+			meridiem, pm = true, m.pm
+			break
+		}
+	}
+
+	// "8h30" and "8h" are common in Vietnamese
+	if h, m, ok := strings.Cut(input, "h"); ok {
+		if m == "" {
+			m = "00"
+		}
+		input = h + ":" + m
+	}
+
+	parts := strings.Split(input, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return pgtype.Time{}, newConvertError[pgtype.Time](s, fmt.Errorf("%w: expected hh:mm or hh:mm:ss", ErrInvalidFormat))
+	}
+	hour, errH := strconv.Atoi(parts[0])
+	minute, errM := strconv.Atoi(parts[1])
+	second := 0.0
+	var errS error
+	if len(parts) == 3 {
+		second, errS = strconv.ParseFloat(parts[2], 64)
+	}
+	if errH != nil || errM != nil || errS != nil || len(parts[1]) != 2 || math.IsNaN(second) || math.IsInf(second, 0) {
+		return pgtype.Time{}, newConvertError[pgtype.Time](s, fmt.Errorf("%w: expected hh:mm or hh:mm:ss", ErrInvalidFormat))
+	}
+
+	if meridiem {
+		if hour < 1 || hour > 12 {
+			return pgtype.Time{}, newConvertError[pgtype.Time](s, fmt.Errorf("%w: hour must be between 1 and 12 with AM/PM", ErrOverflow))
+		}
+		hour %= 12
+		if pm {
+			hour += 12
+		}
+	}
+
+	if hour < 0 || hour > 24 || minute < 0 || minute > 59 || second < 0 || second >= 60 {
+		return pgtype.Time{}, newConvertError[pgtype.Time](s, fmt.Errorf("%w: time must be between 00:00 and 24:00", ErrOverflow))
+	}
+	micros := (int64(hour)*3600+int64(minute)*60)*microsPerSecond + int64(second*float64(microsPerSecond)+0.5)
+	if micros > microsPerDay {
+		return pgtype.Time{}, newConvertError[pgtype.Time](s, fmt.Errorf("%w: time must be between 00:00 and 24:00", ErrOverflow))
+	}
+	return pgtype.Time{Microseconds: micros, Valid: true}, nil
+}
@@ -0,0 +1,188 @@
+package pgxhelpers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ChungNQ511/vnw-helpers/funcvx"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// SetUUIDField sets a string, [16]byte, []byte or fmt.Stringer to a pgtype.UUID
+// It returns a pgtype.UUID with the uuid value and a boolean indicating if the value is valid
+// Strings can be canonical ("6ba7b810-9dad-11d1-80b4-00c04fd430c8"), braced, "urn:uuid:" prefixed or 32 hex digits
+// If the value is empty, not supported or not a uuid, it returns a pgtype.UUID with zero bytes and false
+// It's useful for converting a uuid to a pgtype.UUID
+// @param v any - The value to convert to a pgtype.UUID
+// @return pgtype.UUID - The converted pgtype.UUID
+func SetUUIDField(v any) pgtype.UUID {
+	out, _ := SetUUIDFieldE(v)
+	return out
+}
+
+// SetUUIDFieldE is the strict variant of SetUUIDField
+// It returns a *ConvertError instead of an invalid pgtype.UUID when the value is not supported or not a uuid
+// An empty string, empty []byte or nil is not an error, it returns a pgtype.UUID with false
+// @param v any - The value to convert to a pgtype.UUID
+// @return pgtype.UUID - The converted pgtype.UUID
+// @return error - The conversion error, if any
+func SetUUIDFieldE(v any) (pgtype.UUID, error) {
+	switch val := v.(type) {
+	case nil:
+		return pgtype.UUID{}, nil
+	case pgtype.UUID:
+		return val, nil
+	case *pgtype.UUID:
+		if val == nil {
+			return pgtype.UUID{}, nil
+		}
+		return *val, nil
+	case [16]byte:
+		return pgtype.UUID{Bytes: val, Valid: true}, nil
+	case *[16]byte:
+		if val == nil {
+			return pgtype.UUID{}, nil
+		}
+		return pgtype.UUID{Bytes: *val, Valid: true}, nil
+	case []byte:
+		switch len(val) {
+		case 0:
+			return pgtype.UUID{}, nil
+		case 16:
+			var out pgtype.UUID
+			copy(out.Bytes[:], val)
+			out.Valid = true
+			return out, nil
+		}
+		return stringToPgUUID(string(val))
+	case string:
+		return stringToPgUUID(val)
+	case *string:
+		if val == nil {
+			return pgtype.UUID{}, nil
+		}
+		return stringToPgUUID(*val)
+	case fmt.Stringer:
+		return stringToPgUUID(val.String())
+	default:
+		return pgtype.UUID{}, newConvertError[pgtype.UUID](v, ErrUnsupportedType)
+	}
+}
+
+// stringToPgUUID converts a string to a pgtype.UUID
+// It accepts the canonical form, braces, the "urn:uuid:" prefix and 32 hex digits without hyphens
+// @param s string - The value to convert to a pgtype.UUID
+// @return pgtype.UUID - The converted pgtype.UUID
+// @return error - A *ConvertError if the string is not a uuid
+func stringToPgUUID(s string) (pgtype.UUID, error) {
+	if !funcvx.NotNull(s) {
+		return pgtype.UUID{}, nil
+	}
+
+	raw := strings.ToLower(strings.TrimSpace(s))
+	raw = strings.TrimPrefix(raw, "urn:uuid:")
+	if strings.HasPrefix(raw, "{") && strings.HasSuffix(raw, "}") {
+		raw = raw[1 : len(raw)-1]
+	}
+
+	switch len(raw) {
+	case 36:
+		if raw[8] != '-' || raw[13] != '-' || raw[18] != '-' || raw[23] != '-' {
+			return pgtype.UUID{}, newConvertError[pgtype.UUID](s, fmt.Errorf("%w: misplaced hyphens", ErrInvalidFormat))
+		}
+		raw = raw[:8] + raw[9:13] + raw[14:18] + raw[19:23] + raw[24:]
+	case 32:
+	default:
+		return pgtype.UUID{}, newConvertError[pgtype.UUID](s, fmt.Errorf("%w: wrong length", ErrInvalidFormat))
+	}
+
+	var out pgtype.UUID
+	if _, err := hex.Decode(out.Bytes[:], []byte(raw)); err != nil {
+		return pgtype.UUID{}, newConvertError[pgtype.UUID](s, fmt.Errorf("%w: not hexadecimal", ErrInvalidFormat))
+	}
+	out.Valid = true
+	return out, nil
+}
+
+// formatUUID formats 16 bytes as a canonical lowercase uuid string
+// @param b [16]byte - The uuid bytes
+// @return string - The canonical uuid string
+func formatUUID(b [16]byte) string {
+	var buf [36]byte
+	hex.Encode(buf[0:8], b[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], b[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], b[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], b[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], b[10:])
+	return string(buf[:])
+}
+
+// NewUUIDv4 generates a random (version 4) uuid
+// It's useful for keying new rows without a database round trip
+// @return pgtype.UUID - The generated pgtype.UUID
+// @return error - The error from the random source, if any
+func NewUUIDv4() (pgtype.UUID, error) {
+	var out pgtype.UUID
+	if _, err := rand.Read(out.Bytes[:]); err != nil {
+		return pgtype.UUID{}, err
+	}
+	out.Bytes[6] = (out.Bytes[6] & 0x0f) | 0x40 // version 4
+	out.Bytes[8] = (out.Bytes[8] & 0x3f) | 0x80 // RFC 9562 variant
+	out.Valid = true
+	return out, nil
+}
+
+var (
+	uuidV7Mutex sync.Mutex
+	uuidV7Last  int64  // unix milliseconds of the last generated uuid
+	uuidV7Seq   uint16 // 12 bit counter within the same millisecond
+)
+
+// NewUUIDv7 generates a time-ordered (version 7) uuid
+// The first 48 bits are the unix time in milliseconds, uuids generated in the same process are strictly increasing
+// It's useful for keying new rows with an index-friendly primary key without a database round trip
+// @return pgtype.UUID - The generated pgtype.UUID
+// @return error - The error from the random source, if any
+func NewUUIDv7() (pgtype.UUID, error) {
+	var out pgtype.UUID
+	if _, err := rand.Read(out.Bytes[:]); err != nil {
+		return pgtype.UUID{}, err
+	}
+
+	uuidV7Mutex.Lock()
+	ms := time.Now().UnixMilli()
+	if ms > uuidV7Last {
+		uuidV7Last = ms
+		// start from a random counter, leaving headroom to increment within the millisecond
+		uuidV7Seq = (uint16(out.Bytes[6])<<8 | uint16(out.Bytes[7])) & 0x07ff
+	} else {
+		uuidV7Seq++
+		if uuidV7Seq > 0x0fff {
+			uuidV7Last++
+			uuidV7Seq = 0
+		}
+		ms = uuidV7Last
+	}
+	seq := uuidV7Seq
+	uuidV7Mutex.Unlock()
+
+	out.Bytes[0] = byte(ms >> 40)
+	out.Bytes[1] = byte(ms >> 32)
+	out.Bytes[2] = byte(ms >> 24)
+	out.Bytes[3] = byte(ms >> 16)
+	out.Bytes[4] = byte(ms >> 8)
+	out.Bytes[5] = byte(ms)
+	out.Bytes[6] = 0x70 | byte(seq>>8) // version 7
+	out.Bytes[7] = byte(seq)
+	out.Bytes[8] = (out.Bytes[8] & 0x3f) | 0x80 // RFC 9562 variant
+	out.Valid = true
+	return out, nil
+}
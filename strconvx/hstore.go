@@ -0,0 +1,192 @@
+package strconvx
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ParseHstore parses the text representation of an hstore value
+// Keys and values may be double-quoted with backslash escapes, an unquoted NULL value is returned as a nil pointer
+// If a key appears more than once, the first value is kept like PostgreSQL does
+// If the input is not a valid hstore, it returns an error with the position of the problem
+// It's useful for reading hstore literals such as `"color"=>"red", size=>NULL`
+// @param input string - The hstore text to parse
+// @return map[string]*string - The parsed pairs, empty for an empty input
+// @return error - The parse error, if any
+func ParseHstore(input string) (map[string]*string, error) {
+	result := make(map[string]*string)
+	p := hstoreParser{s: input}
+
+	for {
+		p.skipSpace()
+		if p.done() {
+			return result, nil
+		}
+
+		key, _, err := p.token(func(c byte) bool { return c == '=' })
+		if err != nil {
+			return nil, err
+		}
+
+		p.skipSpace()
+		if !strings.HasPrefix(p.s[p.pos:], "=>") {
+			return nil, p.errorf(`expected "=>"`)
+		}
+		p.pos += 2
+		p.skipSpace()
+
+		value, quoted, err := p.token(func(c byte) bool { return c == ',' })
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := result[key]; !ok {
+			if !quoted && strings.EqualFold(value, "NULL") {
+				result[key] = nil
+			} else {
+				result[key] = &value
+			}
+		}
+
+		p.skipSpace()
+		if p.done() {
+			return result, nil
+		}
+		if p.s[p.pos] != ',' {
+			return nil, p.errorf(`expected ","`)
+		}
+		p.pos++
+	}
+}
+
+// FormatHstore formats pairs as the text representation of an hstore value
+// Keys are sorted, keys and values are double-quoted with '"' and '\' escaped, and nil values are written as NULL
+// It's useful for building hstore literals and logging hstore values
+// @param pairs map[string]*string - The pairs to format
+// @return string - The hstore text, e.g. `"color"=>"red", "size"=>NULL`
+func FormatHstore(pairs map[string]*string) string {
+	keys := make([]string, 0, len(pairs))
+	for k := range pairs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var builder strings.Builder
+	for i, k := range keys {
+		if i > 0 {
+			builder.WriteString(", ")
+		}
+		writeHstoreQuoted(&builder, k)
+		builder.WriteString("=>")
+		if v := pairs[k]; v != nil {
+			writeHstoreQuoted(&builder, *v)
+		} else {
+			builder.WriteString("NULL")
+		}
+	}
+	return builder.String()
+}
+
+// writeHstoreQuoted writes s in double quotes, escaping '"' and '\'
+// @param builder *strings.Builder - The builder to write to
+// @param s string - The key or value to write
+func writeHstoreQuoted(builder *strings.Builder, s string) {
+	builder.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		if s[i] == '"' || s[i] == '\\' {
+			builder.WriteByte('\\')
+		}
+		builder.WriteByte(s[i])
+	}
+	builder.WriteByte('"')
+}
+
+// hstoreParser reads tokens from an hstore text
+// @field s string - The hstore text
+// @field pos int - The byte offset of the next character to read
+type hstoreParser struct {
+	s   string
+	pos int
+}
+
+// done reports whether the whole input has been read
+// @return bool - True if there is nothing left to read
+func (p *hstoreParser) done() bool {
+	return p.pos >= len(p.s)
+}
+
+// skipSpace skips the whitespace PostgreSQL allows between tokens
+// It advances the position to the next non-whitespace character or the end of the input
+func (p *hstoreParser) skipSpace() {
+	for !p.done() && isHstoreSpace(p.s[p.pos]) {
+		p.pos++
+	}
+}
+
+// token reads a quoted token, or an unquoted token ending at whitespace or a stop character
+// A backslash escapes the next character in both forms
+// @param stop func(byte) bool - Reports whether a character ends an unquoted token, e.g. '=' after a key
+// @return string - The token with escapes removed
+// @return bool - True if the token was quoted, so a quoted "NULL" is a string and not a NULL value
+// @return error - A parse error for an empty unquoted token, an unterminated quote or a trailing backslash
+func (p *hstoreParser) token(stop func(byte) bool) (string, bool, error) {
+	if p.done() {
+		return "", false, p.errorf("unexpected end of input")
+	}
+
+	quoted := p.s[p.pos] == '"'
+	if quoted {
+		p.pos++
+	}
+
+	var builder strings.Builder
+	for {
+		if p.done() {
+			if quoted {
+				return "", false, p.errorf("unterminated quoted string")
+			}
+			break
+		}
+
+		c := p.s[p.pos]
+		if quoted && c == '"' {
+			p.pos++
+			break
+		}
+		if !quoted && (isHstoreSpace(c) || stop(c)) {
+			break
+		}
+		if c == '\\' {
+			p.pos++
+			if p.done() {
+				return "", false, p.errorf("unexpected end of input after escape")
+			}
+			c = p.s[p.pos]
+		}
+		builder.WriteByte(c)
+		p.pos++
+	}
+
+	if !quoted && builder.Len() == 0 {
+		return "", false, p.errorf("expected a key or value")
+	}
+	return builder.String(), quoted, nil
+}
+
+// errorf returns a parse error at the current position
+// @param msg string - The description of the problem
+// @return error - The error, with the position in the input
+func (p *hstoreParser) errorf(msg string) error {
+	return fmt.Errorf("strconvx: invalid hstore at position %d: %s", p.pos, msg)
+}
+
+// isHstoreSpace reports whether c is whitespace in the hstore syntax
+// @param c byte - The character
+// @return bool - True for a space, tab, newline, carriage return, form feed or vertical tab
+func isHstoreSpace(c byte) bool {
+	switch c {
+	case ' ', '\t', '\n', '\r', '\f', '\v':
+		return true
+	}
+	return false
+}